@@ -4,41 +4,281 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 type Wrapper interface {
 	String() string
 }
 
+// Statement is a complete SQL statement as returned by the parser.
+type Statement interface {
+	Wrapper
+	SourceSpan() Span
+	SetSourceSpan(span Span)
+}
+
+// Span is the byte range [Start, End) a statement was parsed from, without
+// its terminating semicolon. Nested queries leave it zero.
+type Span struct {
+	Start int
+	End   int
+}
+
+func (s Span) SourceSpan() Span {
+	return s
+}
+
+func (s *Span) SetSourceSpan(span Span) {
+	*s = span
+}
+
+// QueryExpr is a statement that produces rows: a SELECT or a set operation
+// combining several of them.
+type QueryExpr interface {
+	Statement
+	SQLString() string
+}
+
 type SelectStmt struct {
+	Span `json:"-"`
+
+	With        *With
+	Distinct    bool
+	DistinctOn  []Expr // DISTINCT ON (exprs); Distinct is set as well
 	Projections []ProjectionItem
-	From        TableRef
+	From        TableExpr
 	Selection   Expr
-	Limit       *uint64
+	GroupBy     []Expr
+	Having      Expr
+	Windows     []NamedWindow
+	OrderBy     []OrderByItem
+	Limit       Expr
+	LimitAll    bool
+	Offset      Expr
+	Fetch       *Fetch
+}
+
+// With is the WITH [RECURSIVE] list of common table expressions that
+// precedes a query.
+type With struct {
+	Recursive bool
+	CTEs      []CommonTableExpr
+}
+
+type CommonTableExpr struct {
+	Name    Identifier
+	Columns []Identifier
+	Query   QueryExpr
+}
+
+// SetOperation combines the rows of two queries with UNION, INTERSECT or
+// EXCEPT. The ORDER BY and row limiting clauses apply to the combined rows.
+type SetOperation struct {
+	Span `json:"-"`
+
+	With     *With
+	Operator string // union, intersect, except
+	All      bool
+	Left     QueryExpr
+	Right    QueryExpr
+	OrderBy  []OrderByItem
+	Limit    Expr
+	LimitAll bool
+	Offset   Expr
+	Fetch    *Fetch
+}
+
+// ParenQuery is a parenthesized query with a WITH list or result clauses
+// outside the parentheses that cannot be merged with its own, as in
+// (SELECT a FROM t LIMIT 10) ORDER BY a, where the rows are limited before
+// they are sorted.
+type ParenQuery struct {
+	Span `json:"-"`
+
+	With     *With
+	Query    QueryExpr
+	OrderBy  []OrderByItem
+	Limit    Expr
+	LimitAll bool
+	Offset   Expr
+	Fetch    *Fetch
 }
 
 type CreateTableStmt struct {
-	TableName string
-	Columns   []ColumnDef
+	Span `json:"-"`
+
+	Table   *TableRef
+	Columns []ColumnDef
 }
 
 type InsertStmt struct {
-	TableName string
-	Values    []Expr
+	Span `json:"-"`
+
+	Table         *TableRef
+	Columns       []Identifier
+	Rows          [][]Expr  // one entry per parenthesized VALUES row
+	DefaultValues bool      // DEFAULT VALUES instead of a VALUES list
+	Query         QueryExpr // INSERT ... SELECT instead of a VALUES list
+	OnConflict    *OnConflict
+	Returning     []ProjectionItem
+}
+
+// UpdateStmt is UPDATE table SET ... [FROM ...] [WHERE ...] [RETURNING ...].
+type UpdateStmt struct {
+	Span `json:"-"`
+
+	Table     *TableRef
+	Set       []Assignment
+	From      TableExpr
+	Selection Expr
+	Returning []ProjectionItem
+}
+
+// DeleteStmt is DELETE FROM table [USING ...] [WHERE ...] [RETURNING ...].
+type DeleteStmt struct {
+	Span `json:"-"`
+
+	Table     *TableRef
+	Using     TableExpr
+	Selection Expr
+	Returning []ProjectionItem
+}
+
+// TruncateStmt is TRUNCATE [TABLE] name, ... [RESTART IDENTITY] [CASCADE].
+type TruncateStmt struct {
+	Span `json:"-"`
+
+	Tables          []*TableRef
+	RestartIdentity bool
+	Cascade         bool
+}
+
+// MergeStmt is MERGE INTO target USING source ON condition followed by
+// WHEN clauses. For each row the first clause that applies is taken, so
+// their order is kept.
+type MergeStmt struct {
+	Span `json:"-"`
+
+	Target *TableRef
+	Source TableExpr
+	On     Expr
+	Whens  []MergeWhen
+}
+
+// MergeWhen is one WHEN [NOT] MATCHED [AND condition] THEN action clause of
+// a MERGE. Set holds the assignments of an UPDATE; Columns, Values and
+// DefaultValues describe the row of an INSERT.
+type MergeWhen struct {
+	Matched       bool
+	Condition     Expr
+	Action        string // update, delete, insert, nothing
+	Set           []Assignment
+	Columns       []Identifier
+	Values        []Expr
+	DefaultValues bool
+}
+
+// OnConflict is the upsert clause of an INSERT. Postgres writes it
+// ON CONFLICT [target] DO NOTHING | DO UPDATE SET ... [WHERE ...]; the MySQL
+// ON DUPLICATE KEY UPDATE form has no target and always updates. Rows that
+// were proposed for insertion are referred to as EXCLUDED.col.
+type OnConflict struct {
+	DuplicateKey bool         // written as ON DUPLICATE KEY UPDATE
+	Columns      []Identifier // conflict target columns
+	Constraint   Identifier   // conflict target ON CONSTRAINT name
+	Action       string       // nothing, update
+	Set          []Assignment
+	Where        Expr
+}
+
+// Assignment is one item of a SET list. A plain "col = value" has a single
+// column and value; "(a, b) = (x, y)" assigns the values to the columns
+// pairwise, and "(a, b) = (SELECT ...)" has the subquery as its only value.
+type Assignment struct {
+	Columns []Identifier
+	Values  []Expr
 }
 
 type ProjectionItem struct {
 	Expression Expr
+	Alias      Identifier
 	IsWildcard bool
+	Qualifier  []Identifier // table name or alias of a qualified wildcard like u.*
+}
+
+type OrderByItem struct {
+	Expression Expr
+	Direction  string // asc, desc or empty
+	Nulls      string // first, last or empty
+}
+
+// Fetch is the standard FETCH FIRST n ROWS ONLY / WITH TIES clause.
+type Fetch struct {
+	Count    Expr
+	WithTies bool
+}
+
+// TableExpr is anything that can appear in a FROM clause: a table
+// reference or a join of two table expressions.
+type TableExpr interface {
+	TableString() string
+}
+
+// Identifier is one part of a possibly qualified name. Quote is the opening
+// delimiter of a delimited identifier, one of " ` [, and empty for a regular
+// one. Value is the name without delimiters, in the case it was written.
+type Identifier struct {
+	Value string
+	Quote string
 }
 
 type TableRef struct {
-	Name string
+	Qualifier []Identifier // schema or db.schema
+	Name      string
+	Quote     string
+	Alias     Identifier
+}
+
+// DerivedTable is a subquery used as a table: FROM (SELECT ...) AS alias.
+type DerivedTable struct {
+	Query QueryExpr
+	Alias Identifier
+}
+
+type Join struct {
+	Type    string // inner, left, right, full, cross
+	Natural bool
+	Left    TableExpr
+	Right   TableExpr
+	On      Expr
+	Using   []Identifier
+	Alias   Identifier // set on a parenthesized join: (a JOIN b ON ...) AS alias
 }
 
 type ColumnDef struct {
-	Name string
-	Type string
+	Name        Identifier
+	Type        string
+	Constraints []ColumnConstraint
+}
+
+// ColumnConstraint is one constraint of a column definition, in the order
+// written. Name is set when it was introduced by CONSTRAINT name.
+type ColumnConstraint struct {
+	Name       Identifier
+	Type       string      // not null, null, default, primary key, unique, check, references
+	Expression Expr        // the value of DEFAULT or the condition of CHECK
+	References *ForeignKey // the target of REFERENCES
+}
+
+// ForeignKey is the referenced table and columns of a REFERENCES
+// constraint with its ON DELETE and ON UPDATE actions.
+type ForeignKey struct {
+	Table    *TableRef
+	Columns  []Identifier
+	OnDelete string // cascade, restrict, no action, set null, set default
+	OnUpdate string
 }
 
 type Expr interface {
@@ -46,13 +286,29 @@ type Expr interface {
 }
 
 type ColumnRef struct {
-	Name string
+	Qualifier []Identifier // table, schema.table or db.schema.table
+	Name      string
+	Quote     string
 }
 
 type LiteralInt struct {
 	Value int64
 }
 
+type LiteralFloat struct {
+	Value float64
+}
+
+// LiteralDecimal keeps the exact text of a fractional or out of range
+// numeric literal so no precision is lost.
+type LiteralDecimal struct {
+	Value string
+}
+
+type LiteralBool struct {
+	Value bool
+}
+
 type LiteralString struct {
 	Value string
 }
@@ -60,6 +316,134 @@ type LiteralString struct {
 type LiteralNull struct {
 }
 
+// Default is the DEFAULT keyword used as a value in an INSERT row.
+type Default struct {
+}
+
+type FuncCall struct {
+	Name     string
+	Args     []Expr
+	Star     bool // count(*)
+	Distinct bool // count(distinct x)
+	Over     *WindowSpec
+}
+
+// WindowSpec is the window of an OVER clause or a WINDOW clause entry. Name
+// refers to a window defined in the WINDOW clause, either used as is
+// (OVER w) or refined by the other fields (OVER (w ORDER BY x)).
+type WindowSpec struct {
+	Name        Identifier
+	PartitionBy []Expr
+	OrderBy     []OrderByItem
+	Frame       *WindowFrame
+}
+
+// NamedWindow is one "name AS (spec)" entry of a WINDOW clause.
+type NamedWindow struct {
+	Name Identifier
+	Spec *WindowSpec
+}
+
+// WindowFrame is the ROWS, RANGE or GROUPS frame of a window. End is nil
+// when the frame gives only a start bound.
+type WindowFrame struct {
+	Units string // rows, range, groups
+	Start FrameBound
+	End   *FrameBound
+}
+
+type FrameBound struct {
+	Type   string // unbounded preceding, preceding, current row, following, unbounded following
+	Offset Expr   // the n of n PRECEDING and n FOLLOWING
+}
+
+// GroupingSet is a ROLLUP, CUBE or GROUPING SETS item of a GROUP BY clause.
+// Each entry of Sets is one parenthesized column list; a bare column is a
+// list of one.
+type GroupingSet struct {
+	Type string // rollup, cube, grouping sets
+	Sets [][]Expr
+}
+
+// Subquery is a parenthesized SELECT used as a scalar value.
+type Subquery struct {
+	Query QueryExpr
+}
+
+type Exists struct {
+	Query QueryExpr
+}
+
+type InSubquery struct {
+	Expression Expr
+	Query      QueryExpr
+	Not        bool
+}
+
+type InList struct {
+	Expression Expr
+	List       []Expr
+	Not        bool
+}
+
+type Between struct {
+	Expression Expr
+	Low        Expr
+	High       Expr
+	Not        bool
+}
+
+type Like struct {
+	Expression Expr
+	Pattern    Expr
+	Escape     Expr
+	Operator   string // like, ilike
+	Not        bool
+}
+
+type IsNull struct {
+	Expression Expr
+	Not        bool
+}
+
+// CaseExpr is a searched CASE WHEN cond THEN ... END, or a simple
+// CASE operand WHEN value THEN ... END when Operand is set.
+type CaseExpr struct {
+	Operand Expr
+	Whens   []WhenClause
+	Else    Expr
+}
+
+type WhenClause struct {
+	Condition Expr
+	Result    Expr
+}
+
+// Cast is CAST(expr AS type) or expr::type. Type is one of the column
+// types CREATE TABLE accepts.
+type Cast struct {
+	Expression Expr
+	Type       string
+}
+
+// QuantifiedSubquery is the ANY, SOME or ALL (SELECT ...) right-hand side of
+// a comparison.
+type QuantifiedSubquery struct {
+	Quantifier string // any, some, all
+	Query      QueryExpr
+}
+
+// Param is a bind parameter placeholder. Positional parameters are written
+// $n, or ? and numbered in order of appearance; named ones are :name or
+// @name.
+type Param struct {
+	Kind   string // positional, named
+	Marker string // $, ?, : or @
+	Index  int    // number of a positional parameter, starting at 1
+	Name   string // name of a named parameter
+	Pos    int    `json:"-"` // byte offset of the marker in the query
+}
+
 type ComparisonOp struct {
 	Left     Expr
 	Right    Expr
@@ -72,6 +456,17 @@ type LogicalOp struct {
 	Operator string // and, or
 }
 
+type BinaryOp struct {
+	Left     Expr
+	Right    Expr
+	Operator string // +, -, *, /, %, ||
+}
+
+type UnaryOp struct {
+	Operator string // not, -, +
+	Operand  Expr
+}
+
 func (s *SelectStmt) String() string {
 	buffer := &bytes.Buffer{}
 	encoder := json.NewEncoder(buffer)
@@ -81,6 +476,169 @@ func (s *SelectStmt) String() string {
 	return buffer.String()
 }
 
+// SQLString renders the statement back to SQL. It is used to print
+// subqueries nested inside expressions and FROM clauses.
+func (s *SelectStmt) SQLString() string {
+	var sb strings.Builder
+
+	projections := make([]string, 0, len(s.Projections))
+	for _, p := range s.Projections {
+		projections = append(projections, p.SQLString())
+	}
+	writeWith(&sb, s.With)
+	sb.WriteString("SELECT ")
+	if len(s.DistinctOn) > 0 {
+		fmt.Fprintf(&sb, "DISTINCT ON (%s) ", exprList(s.DistinctOn))
+	} else if s.Distinct {
+		sb.WriteString("DISTINCT ")
+	}
+	fmt.Fprintf(&sb, "%s FROM %s", strings.Join(projections, ", "), s.From.TableString())
+
+	if s.Selection != nil {
+		fmt.Fprintf(&sb, " WHERE %s", s.Selection.ExprString())
+	}
+	if len(s.GroupBy) > 0 {
+		fmt.Fprintf(&sb, " GROUP BY %s", exprList(s.GroupBy))
+	}
+	if s.Having != nil {
+		fmt.Fprintf(&sb, " HAVING %s", s.Having.ExprString())
+	}
+	if len(s.Windows) > 0 {
+		windows := make([]string, 0, len(s.Windows))
+		for _, w := range s.Windows {
+			windows = append(windows, fmt.Sprintf("%s AS (%s)", w.Name.SQLString(), w.Spec.SQLString()))
+		}
+		fmt.Fprintf(&sb, " WINDOW %s", strings.Join(windows, ", "))
+	}
+	writeResultClauses(&sb, s.OrderBy, s.Limit, s.LimitAll, s.Offset, s.Fetch)
+
+	return sb.String()
+}
+
+func (s *SetOperation) String() string {
+	res, _ := json.MarshalIndent(s, "", "  ")
+	return string(res)
+}
+
+func (s *SetOperation) SQLString() string {
+	var sb strings.Builder
+
+	operator := s.Operator
+	if s.All {
+		operator += " ALL"
+	}
+	writeWith(&sb, s.With)
+	fmt.Fprintf(&sb, "%s %s %s", setOperand(s.Left), operator, setOperand(s.Right))
+	writeResultClauses(&sb, s.OrderBy, s.Limit, s.LimitAll, s.Offset, s.Fetch)
+
+	return sb.String()
+}
+
+func (p *ParenQuery) String() string {
+	res, _ := json.MarshalIndent(p, "", "  ")
+	return string(res)
+}
+
+func (p *ParenQuery) SQLString() string {
+	var sb strings.Builder
+
+	writeWith(&sb, p.With)
+	fmt.Fprintf(&sb, "(%s)", p.Query.SQLString())
+	writeResultClauses(&sb, p.OrderBy, p.Limit, p.LimitAll, p.Offset, p.Fetch)
+
+	return sb.String()
+}
+
+// setOperand parenthesizes an operand of a set operation unless it is a
+// plain SELECT without a WITH or clauses of its own that would bind to the
+// whole set.
+func setOperand(q QueryExpr) string {
+	if s, ok := q.(*SelectStmt); ok && s.With == nil && s.OrderBy == nil && s.Limit == nil && !s.LimitAll && s.Offset == nil && s.Fetch == nil {
+		return s.SQLString()
+	}
+	return fmt.Sprintf("(%s)", q.SQLString())
+}
+
+func writeWith(sb *strings.Builder, with *With) {
+	if with == nil {
+		return
+	}
+
+	sb.WriteString("WITH ")
+	if with.Recursive {
+		sb.WriteString("RECURSIVE ")
+	}
+	for i, cte := range with.CTEs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(cte.Name.SQLString())
+		if len(cte.Columns) > 0 {
+			fmt.Fprintf(sb, " (%s)", identifierList(cte.Columns))
+		}
+		fmt.Fprintf(sb, " AS (%s)", cte.Query.SQLString())
+	}
+	sb.WriteString(" ")
+}
+
+func writeResultClauses(sb *strings.Builder, orderBy []OrderByItem, limit Expr, limitAll bool, offset Expr, fetch *Fetch) {
+	if len(orderBy) > 0 {
+		items := make([]string, 0, len(orderBy))
+		for _, item := range orderBy {
+			items = append(items, item.SQLString())
+		}
+		fmt.Fprintf(sb, " ORDER BY %s", strings.Join(items, ", "))
+	}
+	if limit != nil {
+		fmt.Fprintf(sb, " LIMIT %s", limit.ExprString())
+	} else if limitAll {
+		sb.WriteString(" LIMIT ALL")
+	}
+	if offset != nil {
+		fmt.Fprintf(sb, " OFFSET %s", offset.ExprString())
+	}
+	if fetch != nil {
+		fmt.Fprintf(sb, " FETCH FIRST %s ROWS", fetch.Count.ExprString())
+		if fetch.WithTies {
+			sb.WriteString(" WITH TIES")
+		} else {
+			sb.WriteString(" ONLY")
+		}
+	}
+}
+
+func (p ProjectionItem) SQLString() string {
+	if p.IsWildcard {
+		if p.Qualifier != nil {
+			return qualifiedName(p.Qualifier, "*")
+		}
+		return "*"
+	}
+	if p.Alias.Value != "" {
+		return fmt.Sprintf("%s AS %s", p.Expression.ExprString(), p.Alias.SQLString())
+	}
+	return p.Expression.ExprString()
+}
+
+func (o OrderByItem) SQLString() string {
+	result := o.Expression.ExprString()
+	if o.Direction != "" {
+		result += " " + o.Direction
+	}
+	if o.Nulls != "" {
+		result += " NULLS " + o.Nulls
+	}
+	return result
+}
+
+func exprList(exprs []Expr) string {
+	items := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		items = append(items, e.ExprString())
+	}
+	return strings.Join(items, ", ")
+}
+
 func (s *CreateTableStmt) String() string {
 	res, _ := json.MarshalIndent(s, "", "  ")
 	return string(res)
@@ -91,26 +649,338 @@ func (s *InsertStmt) String() string {
 	return string(res)
 }
 
+func (s *UpdateStmt) String() string {
+	res, _ := json.MarshalIndent(s, "", "  ")
+	return string(res)
+}
+
+func (s *DeleteStmt) String() string {
+	res, _ := json.MarshalIndent(s, "", "  ")
+	return string(res)
+}
+
+func (s *TruncateStmt) String() string {
+	res, _ := json.MarshalIndent(s, "", "  ")
+	return string(res)
+}
+
+func (s *MergeStmt) String() string {
+	res, _ := json.MarshalIndent(s, "", "  ")
+	return string(res)
+}
+
+func (t *TableRef) TableString() string {
+	name := qualifiedName(t.Qualifier, Identifier{Value: t.Name, Quote: t.Quote}.SQLString())
+	if t.Alias.Value != "" {
+		return fmt.Sprintf("%s %s", name, t.Alias.SQLString())
+	}
+	return name
+}
+
+func (d *DerivedTable) TableString() string {
+	if d.Alias.Value != "" {
+		return fmt.Sprintf("(%s) %s", d.Query.SQLString(), d.Alias.SQLString())
+	}
+	return fmt.Sprintf("(%s)", d.Query.SQLString())
+}
+
+func (j *Join) TableString() string {
+	join := fmt.Sprintf("%s JOIN", j.Type)
+	if j.Natural {
+		join = "NATURAL " + join
+	}
+
+	right := j.Right.TableString()
+	if nested, ok := j.Right.(*Join); ok && nested.Alias.Value == "" {
+		right = fmt.Sprintf("(%s)", right)
+	}
+
+	var result string
+	switch {
+	case j.On != nil:
+		result = fmt.Sprintf("%s %s %s ON %s", j.Left.TableString(), join, right, j.On.ExprString())
+	case len(j.Using) > 0:
+		result = fmt.Sprintf("%s %s %s USING (%s)", j.Left.TableString(), join, right, identifierList(j.Using))
+	default:
+		result = fmt.Sprintf("%s %s %s", j.Left.TableString(), join, right)
+	}
+
+	if j.Alias.Value != "" {
+		return fmt.Sprintf("(%s) %s", result, j.Alias.SQLString())
+	}
+	return result
+}
+
 func (c *ColumnRef) ExprString() string {
-	return c.Name
+	return qualifiedName(c.Qualifier, Identifier{Value: c.Name, Quote: c.Quote}.SQLString())
+}
+
+// SQLString renders the identifier with its delimiters, doubling any
+// closing delimiter inside the name.
+func (i Identifier) SQLString() string {
+	closing := i.Quote
+	switch i.Quote {
+	case "":
+		return i.Value
+	case "[":
+		closing = "]"
+	}
+	return i.Quote + strings.ReplaceAll(i.Value, closing, closing+closing) + closing
+}
+
+// identifierList renders a comma separated list of names.
+func identifierList(names []Identifier) string {
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name.SQLString())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// qualifiedName prefixes an already rendered name with its qualifier.
+func qualifiedName(qualifier []Identifier, name string) string {
+	parts := make([]string, 0, len(qualifier)+1)
+	for _, part := range qualifier {
+		parts = append(parts, part.SQLString())
+	}
+	return strings.Join(append(parts, name), ".")
 }
 
 func (l *LiteralInt) ExprString() string {
 	return fmt.Sprintf("%d", l.Value)
 }
 
+func (l *LiteralFloat) ExprString() string {
+	return strconv.FormatFloat(l.Value, 'g', -1, 64)
+}
+
+func (l *LiteralDecimal) ExprString() string {
+	return l.Value
+}
+
+func (l *LiteralBool) ExprString() string {
+	return strconv.FormatBool(l.Value)
+}
+
 func (l *LiteralString) ExprString() string {
-	return fmt.Sprintf("'%s'", l.Value)
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(l.Value, "'", "''"))
+}
+
+func (f *FuncCall) ExprString() string {
+	var call string
+	switch {
+	case f.Star:
+		call = fmt.Sprintf("%s(*)", f.Name)
+	case f.Distinct:
+		call = fmt.Sprintf("%s(DISTINCT %s)", f.Name, exprList(f.Args))
+	default:
+		call = fmt.Sprintf("%s(%s)", f.Name, exprList(f.Args))
+	}
+
+	if f.Over == nil {
+		return call
+	}
+	if f.Over.Name.Value != "" && f.Over.PartitionBy == nil && f.Over.OrderBy == nil && f.Over.Frame == nil {
+		return fmt.Sprintf("%s OVER %s", call, f.Over.Name.SQLString())
+	}
+	return fmt.Sprintf("%s OVER (%s)", call, f.Over.SQLString())
+}
+
+// SQLString renders the window without its surrounding parentheses.
+func (w *WindowSpec) SQLString() string {
+	var parts []string
+
+	if w.Name.Value != "" {
+		parts = append(parts, w.Name.SQLString())
+	}
+	if len(w.PartitionBy) > 0 {
+		parts = append(parts, "PARTITION BY "+exprList(w.PartitionBy))
+	}
+	if len(w.OrderBy) > 0 {
+		items := make([]string, 0, len(w.OrderBy))
+		for _, item := range w.OrderBy {
+			items = append(items, item.SQLString())
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(items, ", "))
+	}
+	if w.Frame != nil {
+		parts = append(parts, w.Frame.SQLString())
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (f *WindowFrame) SQLString() string {
+	if f.End == nil {
+		return fmt.Sprintf("%s %s", f.Units, f.Start.SQLString())
+	}
+	return fmt.Sprintf("%s BETWEEN %s AND %s", f.Units, f.Start.SQLString(), f.End.SQLString())
+}
+
+func (b FrameBound) SQLString() string {
+	if b.Offset != nil {
+		return fmt.Sprintf("%s %s", b.Offset.ExprString(), b.Type)
+	}
+	return b.Type
+}
+
+func (g *GroupingSet) ExprString() string {
+	sets := make([]string, 0, len(g.Sets))
+	for _, set := range g.Sets {
+		columns := make([]string, 0, len(set))
+		for _, column := range set {
+			columns = append(columns, column.ExprString())
+		}
+
+		if len(set) == 1 {
+			sets = append(sets, columns[0])
+		} else {
+			sets = append(sets, fmt.Sprintf("(%s)", strings.Join(columns, ", ")))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", g.Type, strings.Join(sets, ", "))
+}
+
+func (s *Subquery) ExprString() string {
+	return fmt.Sprintf("(%s)", s.Query.SQLString())
+}
+
+func (e *Exists) ExprString() string {
+	return fmt.Sprintf("EXISTS (%s)", e.Query.SQLString())
+}
+
+func (i *InSubquery) ExprString() string {
+	if i.Not {
+		return fmt.Sprintf("%s NOT IN (%s)", comparisonOperand(i.Expression), i.Query.SQLString())
+	}
+	return fmt.Sprintf("%s IN (%s)", comparisonOperand(i.Expression), i.Query.SQLString())
+}
+
+func (i *InList) ExprString() string {
+	if i.Not {
+		return fmt.Sprintf("%s NOT IN (%s)", comparisonOperand(i.Expression), exprList(i.List))
+	}
+	return fmt.Sprintf("%s IN (%s)", comparisonOperand(i.Expression), exprList(i.List))
+}
+
+func (b *Between) ExprString() string {
+	operator := "BETWEEN"
+	if b.Not {
+		operator = "NOT BETWEEN"
+	}
+	return fmt.Sprintf("%s %s %s AND %s", comparisonOperand(b.Expression), operator, comparisonOperand(b.Low), comparisonOperand(b.High))
+}
+
+func (l *Like) ExprString() string {
+	operator := l.Operator
+	if l.Not {
+		operator = "NOT " + operator
+	}
+
+	result := fmt.Sprintf("%s %s %s", comparisonOperand(l.Expression), operator, comparisonOperand(l.Pattern))
+	if l.Escape != nil {
+		result += " ESCAPE " + comparisonOperand(l.Escape)
+	}
+	return result
+}
+
+func (i *IsNull) ExprString() string {
+	if i.Not {
+		return fmt.Sprintf("%s IS NOT NULL", comparisonOperand(i.Expression))
+	}
+	return fmt.Sprintf("%s IS NULL", comparisonOperand(i.Expression))
+}
+
+func (c *CaseExpr) ExprString() string {
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	if c.Operand != nil {
+		sb.WriteString(" " + c.Operand.ExprString())
+	}
+	for _, when := range c.Whens {
+		fmt.Fprintf(&sb, " WHEN %s THEN %s", when.Condition.ExprString(), when.Result.ExprString())
+	}
+	if c.Else != nil {
+		sb.WriteString(" ELSE " + c.Else.ExprString())
+	}
+	sb.WriteString(" END")
+	return sb.String()
+}
+
+func (c *Cast) ExprString() string {
+	return fmt.Sprintf("CAST(%s AS %s)", c.Expression.ExprString(), c.Type)
+}
+
+func (q *QuantifiedSubquery) ExprString() string {
+	return fmt.Sprintf("%s (%s)", q.Quantifier, q.Query.SQLString())
+}
+
+func (p *Param) ExprString() string {
+	switch p.Marker {
+	case "$":
+		return fmt.Sprintf("$%d", p.Index)
+	case "?":
+		return "?"
+	default:
+		return p.Marker + p.Name
+	}
 }
 
 func (b *ComparisonOp) ExprString() string {
-	return fmt.Sprintf("%s %s %s", b.Left.ExprString(), b.Operator, b.Right.ExprString())
+	return fmt.Sprintf("%s %s %s", comparisonOperand(b.Left), b.Operator, comparisonOperand(b.Right))
+}
+
+// comparisonOperand wraps an operand of a comparison, a predicate or an
+// arithmetic operator in parentheses when it binds no tighter than a
+// comparison, as in (a OR b) = TRUE or (a = 1) = (b = 2).
+func comparisonOperand(e Expr) string {
+	switch e := e.(type) {
+	case *LogicalOp, *ComparisonOp, *InList, *InSubquery, *Between, *Like, *IsNull:
+		return fmt.Sprintf("(%s)", e.ExprString())
+	case *UnaryOp:
+		if e.Operator == "NOT" {
+			return fmt.Sprintf("(%s)", e.ExprString())
+		}
+	}
+	return e.ExprString()
 }
 
 func (l *LogicalOp) ExprString() string {
-	return fmt.Sprintf("%s %s %s", l.Left.ExprString(), l.Operator, l.Right.ExprString())
+	return fmt.Sprintf("%s %s %s", logicalOperand(l.Left, l.Operator), l.Operator, logicalOperand(l.Right, l.Operator))
+}
+
+// logicalOperand wraps a nested logical operation in parentheses when it
+// uses a different operator, so the grouping survives printing.
+func logicalOperand(e Expr, operator string) string {
+	if nested, ok := e.(*LogicalOp); ok && nested.Operator != operator {
+		return fmt.Sprintf("(%s)", nested.ExprString())
+	}
+	return e.ExprString()
+}
+
+func (b *BinaryOp) ExprString() string {
+	return fmt.Sprintf("(%s %s %s)", comparisonOperand(b.Left), b.Operator, comparisonOperand(b.Right))
+}
+
+func (u *UnaryOp) ExprString() string {
+	if u.Operator == "-" || u.Operator == "+" {
+		operand := comparisonOperand(u.Operand)
+		// A signed operand is parenthesized, since "--" would start a comment.
+		if strings.HasPrefix(operand, "-") || strings.HasPrefix(operand, "+") {
+			return fmt.Sprintf("%s(%s)", u.Operator, operand)
+		}
+		return fmt.Sprintf("%s%s", u.Operator, operand)
+	}
+	if _, ok := u.Operand.(*LogicalOp); ok {
+		return fmt.Sprintf("%s (%s)", u.Operator, u.Operand.ExprString())
+	}
+	return fmt.Sprintf("%s %s", u.Operator, u.Operand.ExprString())
 }
 
 func (n *LiteralNull) ExprString() string {
 	return "null"
 }
+
+func (d *Default) ExprString() string {
+	return "DEFAULT"
+}
@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Params returns the bind parameters of a statement in the order they
+// appear in it. Node fields are declared in the order their clauses are
+// usually written, but some clauses, like LIMIT and OFFSET, may be swapped,
+// so the parameters found are sorted by position.
+func Params(stmt Statement) []*Param {
+	var params []*Param
+	collectParams(reflect.ValueOf(stmt), &params)
+	slices.SortStableFunc(params, func(a, b *Param) int {
+		return a.Pos - b.Pos
+	})
+	return params
+}
+
+func collectParams(v reflect.Value, params *[]*Param) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		if p, ok := v.Interface().(*Param); ok {
+			*params = append(*params, p)
+			return
+		}
+		collectParams(v.Elem(), params)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			collectParams(v.Field(i), params)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			collectParams(v.Index(i), params)
+		}
+	}
+}
@@ -14,13 +14,113 @@ const (
 	T_FROM   = "FROM"
 	T_WHERE  = "WHERE"
 	T_LIMIT  = "LIMIT"
-	T_CREATE = "CREATE"
-	T_TABLE  = "TABLE"
-	T_INSERT = "INSERT"
-	T_INTO   = "INTO"
-	T_VALUES = "VALUES"
-	T_AND    = "AND"
-	T_OR     = "OR"
+	T_GROUP  = "GROUP"
+	T_BY     = "BY"
+	T_HAVING = "HAVING"
+	T_ORDER  = "ORDER"
+	T_ASC    = "ASC"
+	T_DESC   = "DESC"
+	T_NULLS  = "NULLS"
+	T_FIRST  = "FIRST"
+	T_LAST   = "LAST"
+	T_ALL    = "ALL"
+	T_OFFSET = "OFFSET"
+	T_FETCH  = "FETCH"
+	T_NEXT   = "NEXT"
+	T_ROW    = "ROW"
+	T_ROWS   = "ROWS"
+	T_ONLY   = "ONLY"
+	T_WITH   = "WITH"
+	T_TIES   = "TIES"
+
+	T_RECURSIVE = "RECURSIVE"
+	T_CREATE    = "CREATE"
+	T_TABLE     = "TABLE"
+	T_INSERT    = "INSERT"
+	T_INTO      = "INTO"
+	T_VALUES    = "VALUES"
+	T_DEFAULT   = "DEFAULT"
+	T_RETURNING = "RETURNING"
+	T_UPDATE    = "UPDATE"
+	T_SET       = "SET"
+	T_DELETE    = "DELETE"
+	T_TRUNCATE  = "TRUNCATE"
+	T_MERGE     = "MERGE"
+	T_MATCHED   = "MATCHED"
+	T_AND       = "AND"
+	T_OR        = "OR"
+	T_NOT       = "NOT"
+	T_TRUE      = "TRUE"
+	T_FALSE     = "FALSE"
+
+	T_CONFLICT   = "CONFLICT"
+	T_CONSTRAINT = "CONSTRAINT"
+	T_DO         = "DO"
+	T_NOTHING    = "NOTHING"
+	T_DUPLICATE  = "DUPLICATE"
+	T_KEY        = "KEY"
+
+	T_RESTART  = "RESTART"
+	T_CONTINUE = "CONTINUE"
+	T_IDENTITY = "IDENTITY"
+	T_CASCADE  = "CASCADE"
+	T_RESTRICT = "RESTRICT"
+
+	T_NULL       = "NULL"
+	T_PRIMARY    = "PRIMARY"
+	T_UNIQUE     = "UNIQUE"
+	T_CHECK      = "CHECK"
+	T_REFERENCES = "REFERENCES"
+	T_NO         = "NO"
+	T_ACTION     = "ACTION"
+
+	T_DISTINCT = "DISTINCT"
+	T_EXISTS   = "EXISTS"
+	T_IN       = "IN"
+	T_ANY      = "ANY"
+	T_SOME     = "SOME"
+	T_BETWEEN  = "BETWEEN"
+	T_LIKE     = "LIKE"
+	T_ILIKE    = "ILIKE"
+	T_ESCAPE   = "ESCAPE"
+	T_IS       = "IS"
+	T_CASE     = "CASE"
+	T_WHEN     = "WHEN"
+	T_THEN     = "THEN"
+	T_ELSE     = "ELSE"
+	T_END      = "END"
+	T_CAST     = "CAST"
+	T_AS       = "AS"
+
+	T_UNION     = "UNION"
+	T_INTERSECT = "INTERSECT"
+	T_EXCEPT    = "EXCEPT"
+
+	T_JOIN    = "JOIN"
+	T_INNER   = "INNER"
+	T_LEFT    = "LEFT"
+	T_RIGHT   = "RIGHT"
+	T_FULL    = "FULL"
+	T_OUTER   = "OUTER"
+	T_CROSS   = "CROSS"
+	T_NATURAL = "NATURAL"
+	T_ON      = "ON"
+	T_USING   = "USING"
+
+	T_ROLLUP   = "ROLLUP"
+	T_CUBE     = "CUBE"
+	T_GROUPING = "GROUPING"
+	T_SETS     = "SETS"
+
+	T_WINDOW    = "WINDOW"
+	T_OVER      = "OVER"
+	T_PARTITION = "PARTITION"
+	T_RANGE     = "RANGE"
+	T_GROUPS    = "GROUPS"
+	T_UNBOUNDED = "UNBOUNDED"
+	T_PRECEDING = "PRECEDING"
+	T_FOLLOWING = "FOLLOWING"
+	T_CURRENT   = "CURRENT"
 
 	T_INT    = "INT"
 	T_BIGINT = "BIGINT"
@@ -33,20 +133,153 @@ const (
 	T_STAR      = "*"
 	T_EQ        = "="
 	T_NEQ       = "!="
+	T_LTGT      = "<>" // the standard spelling of !=
 	T_GT        = ">"
 	T_GTE       = ">="
 	T_LT        = "<"
 	T_LTE       = "<="
+	T_PLUS      = "+"
+	T_MINUS     = "-"
+	T_SLASH     = "/"
+	T_PERCENT   = "%"
+	T_CONCAT    = "||"
+
+	T_DOUBLE_COLON = "::"
+	T_QUESTION     = "?"
+	T_COLON        = ":"
+)
+
+// Binding power of operators in expressions, from loosest to tightest.
+const (
+	precedenceLowest = iota
+	precedenceOr
+	precedenceAnd
+	precedenceNot
+	precedenceComparison
+	precedenceConcat
+	precedenceAdditive
+	precedenceMultiplicative
+	precedenceUnary
+	precedenceCast
+)
+
+// Binding power of set operations, from loosest to tightest.
+const (
+	precedenceUnion = iota + 1
+	precedenceIntersect
 )
 
 var (
+	// selectClauseOrder lists the clauses after FROM in the order they
+	// must appear, except that OFFSET may also precede LIMIT and FETCH.
+	selectClauseOrder = []string{
+		T_WHERE,
+		T_GROUP,
+		T_HAVING,
+		T_WINDOW,
+		T_ORDER,
+		T_LIMIT,
+		T_OFFSET,
+		T_FETCH,
+	}
+
+	setOperationPrecedence = map[string]int{
+		T_UNION:     precedenceUnion,
+		T_EXCEPT:    precedenceUnion,
+		T_INTERSECT: precedenceIntersect,
+	}
+
 	validOps = map[string]struct{}{
-		T_EQ:  {},
-		T_NEQ: {},
-		T_GT:  {},
-		T_GTE: {},
-		T_LT:  {},
-		T_LTE: {},
+		T_EQ:   {},
+		T_NEQ:  {},
+		T_LTGT: {},
+		T_GT:   {},
+		T_GTE:  {},
+		T_LT:   {},
+		T_LTE:  {},
+	}
+
+	binaryPrecedence = map[string]int{
+		T_OR:      precedenceOr,
+		T_AND:     precedenceAnd,
+		T_EQ:      precedenceComparison,
+		T_NEQ:     precedenceComparison,
+		T_LTGT:    precedenceComparison,
+		T_GT:      precedenceComparison,
+		T_GTE:     precedenceComparison,
+		T_LT:      precedenceComparison,
+		T_LTE:     precedenceComparison,
+		T_IN:      precedenceComparison,
+		T_NOT:     precedenceComparison,
+		T_BETWEEN: precedenceComparison,
+		T_LIKE:    precedenceComparison,
+		T_ILIKE:   precedenceComparison,
+		T_IS:      precedenceComparison,
+
+		T_CONCAT:  precedenceConcat,
+		T_PLUS:    precedenceAdditive,
+		T_MINUS:   precedenceAdditive,
+		T_STAR:    precedenceMultiplicative,
+		T_SLASH:   precedenceMultiplicative,
+		T_PERCENT: precedenceMultiplicative,
+
+		T_DOUBLE_COLON: precedenceCast,
+	}
+
+	// predicates are the keyword operators that parsePredicate handles. NOT
+	// is among them because in operator position it can only negate one of
+	// the others.
+	predicates = map[string]struct{}{
+		T_IN:      {},
+		T_NOT:     {},
+		T_BETWEEN: {},
+		T_LIKE:    {},
+		T_ILIKE:   {},
+		T_IS:      {},
+	}
+
+	// operators lists every operator the lexer may glue together, e.g. "=-"
+	// in "a=-1", so the token stream can split them apart again.
+	operators = map[string]struct{}{
+		T_EQ:      {},
+		T_NEQ:     {},
+		T_LTGT:    {},
+		T_GT:      {},
+		T_GTE:     {},
+		T_LT:      {},
+		T_LTE:     {},
+		T_PLUS:    {},
+		T_MINUS:   {},
+		T_STAR:    {},
+		T_SLASH:   {},
+		T_PERCENT: {},
+		T_CONCAT:  {},
+
+		T_DOUBLE_COLON: {},
+	}
+
+	// reservedWords start clauses, so they can never be used as an alias.
+	// Several of them are reported by the lexer as plain identifiers.
+	reservedWords = map[string]struct{}{
+		T_FROM:      {},
+		T_WHERE:     {},
+		T_GROUP:     {},
+		T_HAVING:    {},
+		T_WINDOW:    {},
+		T_ORDER:     {},
+		T_LIMIT:     {},
+		T_OFFSET:    {},
+		T_FETCH:     {},
+		T_ON:        {},
+		T_USING:     {},
+		T_CROSS:     {},
+		T_FULL:      {},
+		T_NATURAL:   {},
+		T_UNION:     {},
+		T_INTERSECT: {},
+		T_EXCEPT:    {},
+		T_RETURNING: {},
+		T_SET:       {},
 	}
 
 	validTypes = map[string]struct{}{
@@ -16,13 +16,13 @@ func parseCreateTableStatement(ts *TokenStream) (*ast.CreateTableStmt, error) {
 		return nil, err
 	}
 
-	tableName, err := ts.ConsumeIdentifier()
+	table, err := parseQualifiedTableName(ts)
 	if err != nil {
-		return nil, fmt.Errorf("%w: expected table name", ErrSyntaxError)
+		return nil, err
 	}
 
 	result := &ast.CreateTableStmt{
-		TableName: tableName,
+		Table: table,
 	}
 
 	if err := ts.Consume(T_LPAREN); err != nil {
@@ -39,9 +39,8 @@ func parseCreateTableStatement(ts *TokenStream) (*ast.CreateTableStmt, error) {
 		return nil, err
 	}
 
-	_, val := ts.Current()
-	if !ts.IsEOF() && strings.ToUpper(val) != T_SEMICOLON {
-		return nil, fmt.Errorf("%w: unexpected token after CREATE TABLE statement", ErrSyntaxError)
+	if err := expectStatementEnd(ts, T_CREATE+" "+T_TABLE); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -51,25 +50,25 @@ func parseColumnDefinitions(ts *TokenStream) ([]ast.ColumnDef, error) {
 	var columns []ast.ColumnDef
 
 	for {
-		columnName, err := ts.ConsumeIdentifier()
+		columnName, err := parseUnqualifiedName(ts, "column name")
 		if err != nil {
-			return nil, fmt.Errorf("%w: expected column name", ErrSyntaxError)
+			return nil, err
 		}
 
-		columnType, err := ts.ConsumeIdentifier()
+		columnType, err := parseDataType(ts)
 		if err != nil {
-			return nil, fmt.Errorf("%w: expected column type", ErrSyntaxError)
+			return nil, err
 		}
 
-		columnType = strings.ToUpper(columnType)
-
-		if _, ok := validTypes[columnType]; !ok {
-			return nil, fmt.Errorf("%w: unsupported column type %s", ErrSyntaxError, columnType)
+		constraints, err := parseColumnConstraints(ts)
+		if err != nil {
+			return nil, err
 		}
 
 		columns = append(columns, ast.ColumnDef{
-			Name: columnName,
-			Type: columnType,
+			Name:        columnName,
+			Type:        columnType,
+			Constraints: constraints,
 		})
 
 		if _, val := ts.Current(); val == T_COMMA {
@@ -85,3 +84,189 @@ func parseColumnDefinitions(ts *TokenStream) ([]ast.ColumnDef, error) {
 
 	return columns, nil
 }
+
+// parseColumnConstraints parses the constraints that follow a column's type
+// up to the next comma or closing parenthesis.
+func parseColumnConstraints(ts *TokenStream) ([]ast.ColumnConstraint, error) {
+	var constraints []ast.ColumnConstraint
+
+	for {
+		if _, val := ts.Current(); ts.IsEOF() || val == T_COMMA || val == T_RPAREN {
+			return constraints, nil
+		}
+
+		constraint, err := parseColumnConstraint(ts)
+		if err != nil {
+			return nil, err
+		}
+		constraints = append(constraints, constraint)
+	}
+}
+
+// parseColumnConstraint parses one [CONSTRAINT name] NOT NULL, NULL,
+// DEFAULT expr, PRIMARY KEY, UNIQUE, CHECK (expr) or REFERENCES constraint.
+func parseColumnConstraint(ts *TokenStream) (ast.ColumnConstraint, error) {
+	result := ast.ColumnConstraint{}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_CONSTRAINT {
+		ts.Next()
+
+		name, err := parseUnqualifiedName(ts, "constraint name")
+		if err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+		result.Name = name
+	}
+
+	_, val := ts.Current()
+	switch strings.ToUpper(val) {
+	case T_NOT:
+		ts.Next()
+		if err := ts.Consume(T_NULL); err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+		result.Type = T_NOT + " " + T_NULL
+	case T_NULL:
+		ts.Next()
+		result.Type = T_NULL
+	case T_DEFAULT:
+		ts.Next()
+
+		// operators looser than arithmetic would run into the constraints
+		// that follow, as NOT does in "DEFAULT 0 NOT NULL"
+		value, err := parseExpressionWithPrecedence(ts, precedenceComparison)
+		if err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+		result.Type = T_DEFAULT
+		result.Expression = value
+	case T_PRIMARY:
+		ts.Next()
+		if err := ts.Consume(T_KEY); err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+		result.Type = T_PRIMARY + " " + T_KEY
+	case T_UNIQUE:
+		ts.Next()
+		result.Type = T_UNIQUE
+	case T_CHECK:
+		ts.Next()
+		if err := ts.Consume(T_LPAREN); err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+
+		condition, err := parseExpression(ts)
+		if err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+		result.Type = T_CHECK
+		result.Expression = condition
+	case T_REFERENCES:
+		ts.Next()
+
+		references, err := parseReferences(ts)
+		if err != nil {
+			return ast.ColumnConstraint{}, err
+		}
+		result.Type = T_REFERENCES
+		result.References = references
+	default:
+		return ast.ColumnConstraint{}, fmt.Errorf("%w: expected column constraint, got %q", ErrSyntaxError, val)
+	}
+
+	return result, nil
+}
+
+// parseReferences parses the rest of REFERENCES table [(columns)] with
+// optional ON DELETE and ON UPDATE actions in either order.
+func parseReferences(ts *TokenStream) (*ast.ForeignKey, error) {
+	table, err := parseQualifiedTableName(ts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: expected table name after REFERENCES", ErrSyntaxError)
+	}
+
+	result := &ast.ForeignKey{Table: table}
+
+	if _, val := ts.Current(); val == T_LPAREN {
+		columns, err := parseIdentifierList(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Columns = columns
+	}
+
+	for {
+		if _, val := ts.Current(); strings.ToUpper(val) != T_ON {
+			return result, nil
+		}
+		ts.Next()
+
+		_, val := ts.Current()
+		event := strings.ToUpper(val)
+		if event != T_DELETE && event != T_UPDATE {
+			return nil, fmt.Errorf("%w: expected DELETE or UPDATE after ON, got %q", ErrSyntaxError, val)
+		}
+		ts.Next()
+
+		action, err := parseReferentialAction(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if event == T_DELETE {
+			result.OnDelete = action
+		} else {
+			result.OnUpdate = action
+		}
+	}
+}
+
+// parseReferentialAction parses CASCADE, RESTRICT, NO ACTION, SET NULL or
+// SET DEFAULT.
+func parseReferentialAction(ts *TokenStream) (string, error) {
+	_, val := ts.Current()
+
+	switch strings.ToUpper(val) {
+	case T_CASCADE, T_RESTRICT:
+		ts.Next()
+		return strings.ToUpper(val), nil
+	case T_NO:
+		ts.Next()
+		if err := ts.Consume(T_ACTION); err != nil {
+			return "", err
+		}
+		return T_NO + " " + T_ACTION, nil
+	case T_SET:
+		ts.Next()
+
+		_, val := ts.Current()
+		if upperVal := strings.ToUpper(val); upperVal == T_NULL || upperVal == T_DEFAULT {
+			ts.Next()
+			return T_SET + " " + upperVal, nil
+		}
+		return "", fmt.Errorf("%w: expected NULL or DEFAULT after SET, got %q", ErrSyntaxError, val)
+	default:
+		return "", fmt.Errorf("%w: expected referential action, got %q", ErrSyntaxError, val)
+	}
+}
+
+// parseDataType reads a type name and checks it against validTypes. It is
+// shared by column definitions and casts.
+func parseDataType(ts *TokenStream) (string, error) {
+	dataType, err := ts.ConsumeIdentifier()
+	if err != nil {
+		return "", fmt.Errorf("%w: expected column type", ErrSyntaxError)
+	}
+
+	dataType = strings.ToUpper(dataType)
+
+	if _, ok := validTypes[dataType]; !ok {
+		return "", fmt.Errorf("%w: unsupported column type %s", ErrSyntaxError, dataType)
+	}
+
+	return dataType, nil
+}
@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"strings"
+
+	"cockatoo/ast"
+)
+
+// parseDeleteStatement parses DELETE FROM table [[AS] alias] followed by
+// optional USING, WHERE and RETURNING clauses.
+func parseDeleteStatement(ts *TokenStream) (*ast.DeleteStmt, error) {
+	if err := ts.Consume(T_DELETE); err != nil {
+		return nil, err
+	}
+
+	if err := ts.Consume(T_FROM); err != nil {
+		return nil, err
+	}
+
+	table, err := parseTableName(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ast.DeleteStmt{
+		Table: table,
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_USING {
+		ts.Next()
+
+		using, err := parseFromClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Using = using
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_WHERE {
+		ts.Next()
+
+		expr, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Selection = expr
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_RETURNING {
+		returning, err := parseReturningClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Returning = returning
+	}
+
+	if err := expectStatementEnd(ts, T_DELETE); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,507 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/go-sqllexer"
+
+	"cockatoo/ast"
+)
+
+// parseExpression parses a full expression using precedence climbing:
+// OR binds loosest, then AND, NOT, comparisons, ||, + and -, * / and %,
+// unary signs, and finally :: casts.
+func parseExpression(ts *TokenStream) (ast.Expr, error) {
+	return parseExpressionWithPrecedence(ts, precedenceLowest)
+}
+
+func parseExpressionWithPrecedence(ts *TokenStream, minPrecedence int) (ast.Expr, error) {
+	left, err := parsePrefixExpression(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		// "a -1" lexes as a column followed by the number -1
+		ts.SplitSign()
+
+		tokenType, val := ts.Current()
+		if tokenType == sqllexer.STRING {
+			break
+		}
+
+		operator := strings.ToUpper(val)
+		precedence, ok := binaryPrecedence[operator]
+		if !ok || precedence <= minPrecedence {
+			break
+		}
+
+		if operator == T_DOUBLE_COLON {
+			ts.Next()
+
+			dataType, err := parseDataType(ts)
+			if err != nil {
+				return nil, err
+			}
+			left = &ast.Cast{Expression: left, Type: dataType}
+			continue
+		}
+
+		// NOT in operator position can only start a negated predicate
+		if _, ok := predicates[operator]; ok {
+			left, err = parsePredicate(ts, left)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		ts.Next()
+
+		if _, ok := validOps[operator]; ok && isQuantifier(ts) {
+			right, err := parseQuantifiedSubquery(ts)
+			if err != nil {
+				return nil, err
+			}
+			left = newBinaryExpression(operator, left, right)
+			continue
+		}
+
+		right, err := parseExpressionWithPrecedence(ts, precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		left = newBinaryExpression(operator, left, right)
+	}
+
+	return left, nil
+}
+
+func newBinaryExpression(operator string, left, right ast.Expr) ast.Expr {
+	if operator == T_AND || operator == T_OR {
+		return &ast.LogicalOp{
+			Left:     left,
+			Right:    right,
+			Operator: operator,
+		}
+	}
+
+	if _, ok := validOps[operator]; ok {
+		return &ast.ComparisonOp{
+			Left:     left,
+			Right:    right,
+			Operator: operator,
+		}
+	}
+
+	return &ast.BinaryOp{
+		Left:     left,
+		Right:    right,
+		Operator: operator,
+	}
+}
+
+func parsePrefixExpression(ts *TokenStream) (ast.Expr, error) {
+	tokenType, val := ts.Current()
+
+	if tokenType != sqllexer.STRING && strings.ToUpper(val) == T_NOT {
+		ts.Next()
+
+		operand, err := parseExpressionWithPrecedence(ts, precedenceNot)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.UnaryOp{Operator: T_NOT, Operand: operand}, nil
+	}
+
+	if tokenType == sqllexer.OPERATOR && (val == T_MINUS || val == T_PLUS) {
+		ts.Next()
+
+		operand, err := parseExpressionWithPrecedence(ts, precedenceUnary)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.UnaryOp{Operator: val, Operand: operand}, nil
+	}
+
+	if tokenType != sqllexer.STRING && strings.ToUpper(val) == T_EXISTS {
+		ts.Next()
+
+		query, err := parseSubquery(ts)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Exists{Query: query}, nil
+	}
+
+	if val == T_LPAREN {
+		ts.Next()
+
+		if isQueryStart(ts) {
+			query, err := parseQuery(ts)
+			if err != nil {
+				return nil, err
+			}
+			if err := ts.Consume(T_RPAREN); err != nil {
+				return nil, err
+			}
+			return &ast.Subquery{Query: query}, nil
+		}
+
+		expr, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	return parsePrimaryExpression(ts)
+}
+
+func parsePrimaryExpression(ts *TokenStream) (ast.Expr, error) {
+	tokenType, val := ts.Current()
+
+	if isLiteralToken(tokenType) {
+		return parseLiteral(ts)
+	}
+
+	if strings.ToUpper(val) == T_CASE && tokenType == sqllexer.KEYWORD {
+		return parseCaseExpression(ts)
+	}
+
+	if isParamStart(ts) {
+		return parseParam(ts)
+	}
+
+	switch tokenType {
+	case sqllexer.IDENT, sqllexer.FUNCTION:
+		ts.Next()
+		if _, next := ts.Current(); next == T_LPAREN && strings.ToUpper(val) == T_CAST {
+			return parseCastExpression(ts)
+		}
+		if _, next := ts.Current(); next == T_LPAREN {
+			return parseFuncCall(ts, val)
+		}
+		if tokenType == sqllexer.FUNCTION {
+			return nil, fmt.Errorf("%w: expected ( after function name %s", ErrSyntaxError, val)
+		}
+		qualifier, column := splitQualifiedName(val)
+		return &ast.ColumnRef{Qualifier: qualifier, Name: column.Value, Quote: column.Quote}, nil
+	case sqllexer.KEYWORD:
+		// Keywords like LEFT, RIGHT and the VALUES(col) of MySQL upserts
+		// are also function names. They are read as a call only when "("
+		// follows without space, the same rule the lexer applies to
+		// identifiers.
+		end := ts.currentPos + len(val)
+		if _, reserved := reservedWords[strings.ToUpper(val)]; reserved || end >= len(ts.query) || ts.query[end] != '(' {
+			return nil, fmt.Errorf("%w: expected expression, got %q", ErrSyntaxError, val)
+		}
+		ts.Next()
+		return parseFuncCall(ts, val)
+	default:
+		return nil, fmt.Errorf("%w: expected expression, got %q", ErrSyntaxError, val)
+	}
+}
+
+// parseCaseExpression parses a searched CASE WHEN cond THEN ... END or, when
+// an operand follows CASE, a simple CASE x WHEN value THEN ... END.
+func parseCaseExpression(ts *TokenStream) (*ast.CaseExpr, error) {
+	if err := ts.Consume(T_CASE); err != nil {
+		return nil, err
+	}
+
+	result := &ast.CaseExpr{}
+
+	if _, val := ts.Current(); strings.ToUpper(val) != T_WHEN {
+		operand, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Operand = operand
+	}
+
+	for {
+		if _, val := ts.Current(); strings.ToUpper(val) != T_WHEN {
+			break
+		}
+		ts.Next()
+
+		condition, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_THEN); err != nil {
+			return nil, err
+		}
+
+		value, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Whens = append(result.Whens, ast.WhenClause{Condition: condition, Result: value})
+	}
+
+	if len(result.Whens) == 0 {
+		return nil, fmt.Errorf("%w: CASE expression must have at least one WHEN clause", ErrSyntaxError)
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_ELSE {
+		ts.Next()
+
+		value, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Else = value
+	}
+
+	if err := ts.Consume(T_END); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseCastExpression parses the (expr AS type) part of CAST once the CAST
+// keyword has been consumed.
+func parseCastExpression(ts *TokenStream) (*ast.Cast, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	expr, err := parseExpression(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.Consume(T_AS); err != nil {
+		return nil, err
+	}
+
+	dataType, err := parseDataType(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	return &ast.Cast{Expression: expr, Type: dataType}, nil
+}
+
+// parseFuncCall parses the argument list of a function call whose name has
+// already been consumed: name(*), name(DISTINCT a) or name(a, b, ...),
+// followed by an optional OVER clause.
+func parseFuncCall(ts *TokenStream, name string) (*ast.FuncCall, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	result := &ast.FuncCall{Name: name}
+
+	if _, val := ts.Current(); val == T_STAR {
+		ts.Next()
+		result.Star = true
+	} else if _, val := ts.Current(); val != T_RPAREN {
+		if _, val := ts.Current(); strings.ToUpper(val) == T_DISTINCT {
+			ts.Next()
+			result.Distinct = true
+		}
+
+		for {
+			arg, err := parseExpression(ts)
+			if err != nil {
+				return nil, err
+			}
+			result.Args = append(result.Args, arg)
+
+			if _, val := ts.Current(); val == T_COMMA {
+				ts.Next()
+				continue
+			}
+			break
+		}
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_OVER {
+		over, err := parseOverClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Over = over
+	}
+
+	return result, nil
+}
+
+// parseSubquery parses a parenthesized query.
+func parseSubquery(ts *TokenStream) (ast.QueryExpr, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	query, err := parseQuery(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	return query, nil
+}
+
+// parsePredicate parses the predicate that follows left: [NOT] IN,
+// [NOT] BETWEEN, [NOT] LIKE / ILIKE and IS [NOT] NULL.
+func parsePredicate(ts *TokenStream, left ast.Expr) (ast.Expr, error) {
+	not := false
+	if _, val := ts.Current(); strings.ToUpper(val) == T_NOT {
+		not = true
+		ts.Next()
+	}
+
+	_, val := ts.Current()
+	predicate := strings.ToUpper(val)
+
+	switch predicate {
+	case T_IN:
+		ts.Next()
+		return parseInPredicate(ts, left, not)
+	case T_BETWEEN:
+		ts.Next()
+
+		low, err := parseExpressionWithPrecedence(ts, precedenceComparison)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_AND); err != nil {
+			return nil, err
+		}
+
+		high, err := parseExpressionWithPrecedence(ts, precedenceComparison)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.Between{Expression: left, Low: low, High: high, Not: not}, nil
+	case T_LIKE, T_ILIKE:
+		ts.Next()
+
+		pattern, err := parseExpressionWithPrecedence(ts, precedenceComparison)
+		if err != nil {
+			return nil, err
+		}
+		result := &ast.Like{Expression: left, Pattern: pattern, Operator: predicate, Not: not}
+
+		if _, val := ts.Current(); strings.ToUpper(val) == T_ESCAPE {
+			ts.Next()
+
+			escape, err := parseExpressionWithPrecedence(ts, precedenceComparison)
+			if err != nil {
+				return nil, err
+			}
+			result.Escape = escape
+		}
+
+		return result, nil
+	case T_IS:
+		if not {
+			return nil, fmt.Errorf("%w: unexpected NOT before IS", ErrSyntaxError)
+		}
+		ts.Next()
+
+		if _, val := ts.Current(); strings.ToUpper(val) == T_NOT {
+			not = true
+			ts.Next()
+		}
+
+		if tokenType, val := ts.Current(); tokenType != sqllexer.NULL {
+			return nil, fmt.Errorf("%w: expected NULL after IS, got %q", ErrSyntaxError, val)
+		}
+		ts.Next()
+
+		return &ast.IsNull{Expression: left, Not: not}, nil
+	default:
+		return nil, fmt.Errorf("%w: expected IN, BETWEEN, LIKE or ILIKE after NOT, got %q", ErrSyntaxError, val)
+	}
+}
+
+// parseInPredicate parses the subquery or value list after IN.
+func parseInPredicate(ts *TokenStream, left ast.Expr, not bool) (ast.Expr, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	if isQueryStart(ts) {
+		query, err := parseQuery(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return nil, err
+		}
+
+		return &ast.InSubquery{Expression: left, Query: query, Not: not}, nil
+	}
+
+	var list []ast.Expr
+	for {
+		item, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	return &ast.InList{Expression: left, List: list, Not: not}, nil
+}
+
+func isQuantifier(ts *TokenStream) bool {
+	_, val := ts.Current()
+	switch strings.ToUpper(val) {
+	case T_ANY, T_SOME, T_ALL:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseQuantifiedSubquery parses the ANY, SOME or ALL (SELECT ...) operand
+// on the right of a comparison.
+func parseQuantifiedSubquery(ts *TokenStream) (*ast.QuantifiedSubquery, error) {
+	_, val := ts.Current()
+	quantifier := strings.ToUpper(val)
+	ts.Next()
+
+	query, err := parseSubquery(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.QuantifiedSubquery{Quantifier: quantifier, Query: query}, nil
+}
@@ -0,0 +1,289 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/go-sqllexer"
+
+	"cockatoo/ast"
+)
+
+// parseFromClause parses the table expressions after FROM. Items separated
+// by commas become cross joins; explicit JOINs bind tighter than commas, so
+// "a, b JOIN c ON ..." is a CROSS (b JOIN c).
+func parseFromClause(ts *TokenStream) (ast.TableExpr, error) {
+	from, err := parseJoinedTable(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		if _, val := ts.Current(); val != T_COMMA {
+			return from, nil
+		}
+		ts.Next()
+
+		right, err := parseJoinedTable(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		from = &ast.Join{
+			Type:  T_CROSS,
+			Left:  from,
+			Right: right,
+		}
+	}
+}
+
+// parseJoinedTable parses a table followed by any number of JOINs, which
+// associate to the left.
+func parseJoinedTable(ts *TokenStream) (ast.TableExpr, error) {
+	left, err := parseTablePrimary(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		join, ok, err := parseJoinType(ts)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return left, nil
+		}
+
+		right, err := parseTablePrimary(ts)
+		if err != nil {
+			return nil, err
+		}
+		join.Left = left
+		join.Right = right
+
+		if err := parseJoinCondition(ts, join); err != nil {
+			return nil, err
+		}
+
+		left = join
+	}
+}
+
+// parseJoinType consumes the keywords that introduce a join, up to and
+// including JOIN. It reports false when the current token does not start
+// a join.
+func parseJoinType(ts *TokenStream) (*ast.Join, bool, error) {
+	join := &ast.Join{Type: T_INNER}
+
+	_, val := ts.Current()
+	upperVal := strings.ToUpper(val)
+
+	if upperVal == T_NATURAL {
+		join.Natural = true
+		ts.Next()
+		_, val = ts.Current()
+		upperVal = strings.ToUpper(val)
+	}
+
+	switch upperVal {
+	case T_JOIN:
+	case T_INNER, T_CROSS:
+		join.Type = upperVal
+		ts.Next()
+	case T_LEFT, T_RIGHT, T_FULL:
+		join.Type = upperVal
+		ts.Next()
+		if _, val := ts.Current(); strings.ToUpper(val) == T_OUTER {
+			ts.Next()
+		}
+	default:
+		if join.Natural {
+			return nil, false, fmt.Errorf("%w: expected JOIN after NATURAL, got %q", ErrSyntaxError, val)
+		}
+		return nil, false, nil
+	}
+
+	if join.Natural && join.Type == T_CROSS {
+		return nil, false, fmt.Errorf("%w: NATURAL CROSS JOIN is not allowed", ErrSyntaxError)
+	}
+
+	if err := ts.Consume(T_JOIN); err != nil {
+		return nil, false, err
+	}
+
+	return join, true, nil
+}
+
+// parseJoinCondition reads the ON or USING clause a join requires. Cross and
+// natural joins take no condition.
+func parseJoinCondition(ts *TokenStream, join *ast.Join) error {
+	if join.Type == T_CROSS || join.Natural {
+		return nil
+	}
+
+	_, val := ts.Current()
+	switch strings.ToUpper(val) {
+	case T_ON:
+		ts.Next()
+
+		condition, err := parseExpression(ts)
+		if err != nil {
+			return err
+		}
+		join.On = condition
+		return nil
+	case T_USING:
+		ts.Next()
+
+		columns, err := parseIdentifierList(ts)
+		if err != nil {
+			return err
+		}
+		join.Using = columns
+		return nil
+	default:
+		return fmt.Errorf("%w: expected ON or USING after %s JOIN", ErrSyntaxError, join.Type)
+	}
+}
+
+// parseTablePrimary parses a single table reference with an optional alias,
+// a derived table, or a parenthesized join.
+func parseTablePrimary(ts *TokenStream) (ast.TableExpr, error) {
+	if _, val := ts.Current(); val == T_LPAREN {
+		ts.Next()
+
+		if isQueryStart(ts) {
+			return parseDerivedTable(ts)
+		}
+
+		table, err := parseFromClause(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return nil, err
+		}
+
+		// a parenthesized join may be given an alias, and so may a derived
+		// table wrapped in extra parentheses: ((SELECT ...)) AS alias
+		alias, err := parseAlias(ts)
+		if err != nil {
+			return nil, err
+		}
+		if alias.Value == "" {
+			return table, nil
+		}
+
+		switch t := table.(type) {
+		case *ast.Join:
+			if t.Alias.Value == "" {
+				t.Alias = alias
+				return t, nil
+			}
+		case *ast.DerivedTable:
+			if t.Alias.Value == "" {
+				t.Alias = alias
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: unexpected alias %s after parenthesized table", ErrSyntaxError, alias.SQLString())
+	}
+
+	return parseTableName(ts)
+}
+
+// parseDerivedTable parses the rest of "(SELECT ...) [AS] alias" after the
+// opening parenthesis.
+func parseDerivedTable(ts *TokenStream) (*ast.DerivedTable, error) {
+	query, err := parseQuery(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	alias, err := parseAlias(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.DerivedTable{Query: query, Alias: alias}, nil
+}
+
+// parseTableName parses a table name followed by an optional alias.
+func parseTableName(ts *TokenStream) (*ast.TableRef, error) {
+	result, err := parseQualifiedTableName(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	alias, err := parseAlias(ts)
+	if err != nil {
+		return nil, err
+	}
+	result.Alias = alias
+
+	return result, nil
+}
+
+// parseQualifiedTableName parses a possibly qualified table name, for
+// statements where no alias may follow.
+func parseQualifiedTableName(ts *TokenStream) (*ast.TableRef, error) {
+	tableName, err := ts.ConsumeIdentifier()
+	if err != nil {
+		return nil, fmt.Errorf("%w: expected table name", ErrSyntaxError)
+	}
+
+	qualifier, table := splitQualifiedName(tableName)
+	result := &ast.TableRef{
+		Qualifier: qualifier,
+		Name:      table.Value,
+		Quote:     table.Quote,
+	}
+
+	return result, nil
+}
+
+// parseIdentifierList parses a parenthesized, comma separated list of names
+// such as the columns of a USING clause.
+func parseIdentifierList(ts *TokenStream) ([]ast.Identifier, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	var names []ast.Identifier
+	for {
+		name, err := parseUnqualifiedName(ts, "column name")
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
+
+// isReservedWord reports whether the current token is a keyword that starts
+// a clause, so it must not be taken as an alias.
+func isReservedWord(ts *TokenStream) bool {
+	tokenType, val := ts.Current()
+	if tokenType != sqllexer.IDENT && tokenType != sqllexer.KEYWORD {
+		return false
+	}
+
+	_, ok := reservedWords[strings.ToUpper(val)]
+	return ok
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"cockatoo/ast"
+)
+
+// closingQuotes maps the opening delimiter of a delimited identifier to its
+// closing one: "Order", `order` (MySQL) and [order] (SQL Server).
+var closingQuotes = map[byte]byte{
+	'"': '"',
+	'`': '`',
+	'[': ']',
+}
+
+// scanNamePart returns the end of the identifier starting at query[start]:
+// either a delimited identifier, in which a doubled closing delimiter stands
+// for itself, or a regular one made of letters, digits, '_', '$' and '#'.
+func scanNamePart(query string, start int) (int, bool) {
+	if start >= len(query) {
+		return 0, false
+	}
+
+	if closing, ok := closingQuotes[query[start]]; ok {
+		for i := start + 1; i < len(query); i++ {
+			if query[i] != closing {
+				continue
+			}
+			if i+1 < len(query) && query[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1, true
+		}
+		return 0, false
+	}
+
+	end := start
+	for end < len(query) {
+		r, size := utf8.DecodeRuneInString(query[end:])
+		if !unicode.IsLetter(r) && r != '_' && (end == start || !unicode.IsDigit(r) && r != '$' && r != '#') {
+			break
+		}
+		end += size
+	}
+
+	return end, end > start
+}
+
+// splitName splits the text of a possibly qualified name, as read by
+// rescanName, into its parts. Delimiters are removed from quoted parts but
+// remembered in Quote, and their case is kept as written.
+func splitName(name string) []ast.Identifier {
+	var parts []ast.Identifier
+
+	for start := 0; start < len(name); {
+		end, ok := scanNamePart(name, start)
+		if !ok {
+			break
+		}
+
+		part := ast.Identifier{Value: name[start:end]}
+		if closing, ok := closingQuotes[name[start]]; ok {
+			part.Quote = name[start : start+1]
+			part.Value = strings.ReplaceAll(name[start+1:end-1], string([]byte{closing, closing}), string(closing))
+		}
+		parts = append(parts, part)
+
+		// skip the dot separating this part from the next
+		start = end + 1
+	}
+
+	return parts
+}
+
+// splitQualifiedName splits a name into the qualifier parts and the final,
+// unqualified part. The qualifier is nil for a name with one part.
+func splitQualifiedName(name string) ([]ast.Identifier, ast.Identifier) {
+	parts := splitName(name)
+	switch len(parts) {
+	case 0:
+		// not a name rescanName could read; keep the lexer's text
+		return nil, ast.Identifier{Value: name}
+	case 1:
+		return nil, parts[0]
+	}
+	return parts[:len(parts)-1], parts[len(parts)-1]
+}
+
+// parseUnqualifiedName consumes a name that must have a single part, such as
+// a column in a column list or an alias. what names the kind of name in
+// error messages.
+func parseUnqualifiedName(ts *TokenStream, what string) (ast.Identifier, error) {
+	_, val := ts.Current()
+	name, err := ts.ConsumeIdentifier()
+	if err != nil {
+		return ast.Identifier{}, fmt.Errorf("%w: expected %s, got %q", ErrSyntaxError, what, val)
+	}
+
+	qualifier, result := splitQualifiedName(name)
+	if qualifier != nil {
+		return ast.Identifier{}, fmt.Errorf("%w: expected unqualified %s, got %q", ErrSyntaxError, what, name)
+	}
+	return result, nil
+}
@@ -2,14 +2,14 @@ package parser
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
-	"github.com/DataDog/go-sqllexer"
-
 	"cockatoo/ast"
 )
 
+// parseInsertStatement parses INSERT INTO table [(columns)] followed by
+// VALUES (row), ..., a query or DEFAULT VALUES, an optional upsert clause
+// and an optional RETURNING list.
 func parseInsertStatement(ts *TokenStream) (*ast.InsertStmt, error) {
 	if err := ts.Consume(T_INSERT); err != nil {
 		return nil, err
@@ -19,33 +19,218 @@ func parseInsertStatement(ts *TokenStream) (*ast.InsertStmt, error) {
 		return nil, err
 	}
 
-	tableName, err := ts.ConsumeIdentifier()
+	table, err := parseQualifiedTableName(ts)
 	if err != nil {
-		return nil, fmt.Errorf("%w: expected table name", ErrSyntaxError)
+		return nil, err
 	}
 
 	result := &ast.InsertStmt{
-		TableName: tableName,
+		Table: table,
+	}
+
+	if _, val := ts.Current(); val == T_LPAREN && !isParenthesizedQuery(ts) {
+		columns, err := parseIdentifierList(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Columns = columns
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_DEFAULT {
+		if result.Columns != nil {
+			return nil, fmt.Errorf("%w: DEFAULT VALUES cannot follow a column list", ErrSyntaxError)
+		}
+		ts.Next()
+		if err := ts.Consume(T_VALUES); err != nil {
+			return nil, err
+		}
+		result.DefaultValues = true
+	} else if isQueryStart(ts) || isParenthesizedQuery(ts) {
+		query, err := parseQuery(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Query = query
+	} else {
+		if err := ts.Consume(T_VALUES); err != nil {
+			return nil, err
+		}
+
+		rows, err := parseValuesRows(ts, len(result.Columns))
+		if err != nil {
+			return nil, err
+		}
+		result.Rows = rows
 	}
 
-	if err := ts.Consume(T_VALUES); err != nil {
+	if _, val := ts.Current(); strings.ToUpper(val) == T_ON {
+		onConflict, err := parseOnConflictClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.OnConflict = onConflict
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_RETURNING {
+		returning, err := parseReturningClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Returning = returning
+	}
+
+	if err := expectStatementEnd(ts, T_INSERT); err != nil {
 		return nil, err
 	}
 
-	values, err := parseValuesList(ts)
-	if err != nil {
+	return result, nil
+}
+
+// parseOnConflictClause parses ON CONFLICT [(columns) | ON CONSTRAINT name]
+// DO NOTHING | DO UPDATE SET ... [WHERE ...], or ON DUPLICATE KEY UPDATE ...
+func parseOnConflictClause(ts *TokenStream) (*ast.OnConflict, error) {
+	if err := ts.Consume(T_ON); err != nil {
 		return nil, err
 	}
-	result.Values = values
+
+	result := &ast.OnConflict{}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_DUPLICATE {
+		ts.Next()
+		if err := ts.Consume(T_KEY); err != nil {
+			return nil, err
+		}
+		if err := ts.Consume(T_UPDATE); err != nil {
+			return nil, err
+		}
+
+		set, err := parseAssignmentList(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.DuplicateKey = true
+		result.Action = T_UPDATE
+		result.Set = set
+
+		return result, nil
+	}
+
+	if err := ts.Consume(T_CONFLICT); err != nil {
+		return nil, fmt.Errorf("%w: expected CONFLICT or DUPLICATE KEY after ON", ErrSyntaxError)
+	}
 
 	_, val := ts.Current()
-	if !ts.IsEOF() && strings.ToUpper(val) != T_SEMICOLON {
-		return nil, fmt.Errorf("%w: unexpected token after INSERT statement", ErrSyntaxError)
+	switch strings.ToUpper(val) {
+	case T_LPAREN:
+		columns, err := parseIdentifierList(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Columns = columns
+	case T_ON:
+		ts.Next()
+		if err := ts.Consume(T_CONSTRAINT); err != nil {
+			return nil, err
+		}
+
+		name, err := parseUnqualifiedName(ts, "constraint name")
+		if err != nil {
+			return nil, err
+		}
+		result.Constraint = name
+	}
+
+	if err := ts.Consume(T_DO); err != nil {
+		return nil, err
+	}
+
+	_, val = ts.Current()
+	switch strings.ToUpper(val) {
+	case T_NOTHING:
+		ts.Next()
+		result.Action = T_NOTHING
+	case T_UPDATE:
+		ts.Next()
+		if err := ts.Consume(T_SET); err != nil {
+			return nil, err
+		}
+
+		set, err := parseAssignmentList(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Action = T_UPDATE
+		result.Set = set
+
+		if _, val := ts.Current(); strings.ToUpper(val) == T_WHERE {
+			ts.Next()
+
+			where, err := parseExpression(ts)
+			if err != nil {
+				return nil, err
+			}
+			result.Where = where
+		}
+	default:
+		return nil, fmt.Errorf("%w: expected NOTHING or UPDATE after DO, got %q", ErrSyntaxError, val)
 	}
 
 	return result, nil
 }
 
+// parseValueOrDefault parses a value of a VALUES row or SET list, where
+// DEFAULT may stand in for an expression.
+func parseValueOrDefault(ts *TokenStream) (ast.Expr, error) {
+	if _, val := ts.Current(); strings.ToUpper(val) == T_DEFAULT {
+		ts.Next()
+		return &ast.Default{}, nil
+	}
+
+	return parseExpression(ts)
+}
+
+// parseReturningClause parses RETURNING followed by a select list.
+func parseReturningClause(ts *TokenStream) ([]ast.ProjectionItem, error) {
+	if err := ts.Consume(T_RETURNING); err != nil {
+		return nil, err
+	}
+
+	return parseProjectionList(ts)
+}
+
+// parseValuesRows parses the comma separated rows of a VALUES list. Every
+// row must have as many values as the first one, and as columns when a
+// column list was given.
+func parseValuesRows(ts *TokenStream, columns int) ([][]ast.Expr, error) {
+	var rows [][]ast.Expr
+
+	for {
+		row, err := parseValuesList(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if columns > 0 && len(row) != columns {
+			return nil, fmt.Errorf("%w: INSERT has %d columns but row %d has %d values",
+				ErrSyntaxError, columns, len(rows)+1, len(row))
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("%w: VALUES rows must all have the same number of values",
+				ErrSyntaxError)
+		}
+		rows = append(rows, row)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	return rows, nil
+}
+
+// parseValuesList parses one parenthesized row of values.
 func parseValuesList(ts *TokenStream) ([]ast.Expr, error) {
 	var values []ast.Expr
 
@@ -54,7 +239,7 @@ func parseValuesList(ts *TokenStream) ([]ast.Expr, error) {
 	}
 
 	for {
-		value, err := parseValue(ts)
+		value, err := parseValueOrDefault(ts)
 		if err != nil {
 			return nil, err
 		}
@@ -77,25 +262,3 @@ func parseValuesList(ts *TokenStream) ([]ast.Expr, error) {
 
 	return values, nil
 }
-
-func parseValue(ts *TokenStream) (ast.Expr, error) {
-	tokenType, val := ts.Current()
-
-	switch tokenType {
-	case sqllexer.NUMBER:
-		intVal, err := strconv.ParseInt(val, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("%w: invalid integer", ErrSyntaxError)
-		}
-		ts.Next()
-		return &ast.LiteralInt{Value: intVal}, nil
-	case sqllexer.STRING:
-		ts.Next()
-		return &ast.LiteralString{Value: val[1 : len(val)-1]}, nil
-	case sqllexer.NULL:
-		ts.Next()
-		return &ast.LiteralNull{}, nil
-	default:
-		return nil, fmt.Errorf("%w: expected value", ErrSyntaxError)
-	}
-}
@@ -13,9 +13,16 @@ type TokenStream struct {
 	lexer       *sqllexer.Lexer
 	currentType sqllexer.TokenType
 	currentVal  string
+	currentPos  int // byte offset of the current token in query
+	prevEnd     int // byte offset just past the previous token
+	offset      int // byte offset the lexer resumes scanning from
 	query       string
 	initialized bool
 	atEOF       bool
+
+	// positionalParams counts the ? placeholders of the current statement,
+	// which are numbered in order of appearance
+	positionalParams int
 }
 
 func NewTokenStream(query string) *TokenStream {
@@ -29,15 +36,7 @@ func NewTokenStream(query string) *TokenStream {
 func (ts *TokenStream) Initialize() {
 	if !ts.initialized {
 		// read first token
-		t := ts.lexer.Scan()
-		if t.Type != sqllexer.EOF {
-			ts.currentVal = t.Value
-			ts.currentType = t.Type
-		} else {
-			ts.currentVal = ""
-			ts.atEOF = true
-		}
-
+		ts.scan()
 		ts.initialized = true
 	}
 }
@@ -52,20 +51,192 @@ func (ts *TokenStream) Next() (sqllexer.TokenType, string) {
 		return ts.currentType, ts.currentVal
 	}
 
+	ts.scan()
+	return ts.currentType, ts.currentVal
+}
+
+func (ts *TokenStream) scan() {
+	ts.prevEnd = ts.currentPos + len(ts.currentVal)
+
 	t := ts.lexer.Scan()
-	for t.Type == sqllexer.SPACE {
+	for isTrivia(t.Type) {
+		ts.offset += len(t.Value)
 		t = ts.lexer.Scan()
-		continue
 	}
 
 	ts.currentType = t.Type
 	ts.currentVal = t.Value
+	ts.currentPos = ts.offset
+	ts.offset += len(t.Value)
 
 	if ts.currentType == sqllexer.EOF {
+		ts.currentVal = ""
 		ts.atEOF = true
+		return
 	}
 
-	return ts.currentType, ts.currentVal
+	switch {
+	case ts.currentType == sqllexer.STRING || ts.currentType == sqllexer.INCOMPLETE_STRING:
+		ts.rescanString()
+	case isNameStart(ts.currentType, ts.currentVal):
+		ts.rescanName()
+	case ts.currentType == sqllexer.PUNCTUATION && ts.currentVal == ".":
+		ts.joinLeadingDot()
+	}
+	ts.splitGluedToken()
+}
+
+// isTrivia reports whether a token carries no meaning for the parser.
+func isTrivia(tokenType sqllexer.TokenType) bool {
+	return tokenType == sqllexer.SPACE ||
+		tokenType == sqllexer.COMMENT ||
+		tokenType == sqllexer.MULTILINE_COMMENT
+}
+
+// rescanString re-reads a string literal with SQL standard rules, where a
+// doubled quote is the only escape. The lexer instead ends a string at the
+// first quote, splitting a literal with a doubled quote in two, and treats
+// backslash as an escape, so '\' runs on to the end of the query.
+func (ts *TokenStream) rescanString() {
+	if !strings.HasPrefix(ts.currentVal, "'") {
+		return
+	}
+
+	for i := ts.currentPos + 1; i < len(ts.query); i++ {
+		if ts.query[i] != '\'' {
+			continue
+		}
+		if i+1 < len(ts.query) && ts.query[i+1] == '\'' {
+			i++
+			continue
+		}
+
+		ts.currentType = sqllexer.STRING
+		ts.currentVal = ts.query[ts.currentPos : i+1]
+		ts.offset = i + 1
+		ts.lexer = sqllexer.New(ts.query[ts.offset:])
+		return
+	}
+
+	ts.currentType = sqllexer.INCOMPLETE_STRING
+	ts.currentVal = ts.query[ts.currentPos:]
+	ts.offset = len(ts.query)
+	ts.lexer = sqllexer.New("")
+}
+
+// isNameStart reports whether a token can begin a name. Besides identifiers
+// this includes the backtick and bracket that the lexer returns on their own
+// when they open a delimited identifier.
+func isNameStart(tokenType sqllexer.TokenType, val string) bool {
+	switch tokenType {
+	case sqllexer.IDENT, sqllexer.QUOTED_IDENT, sqllexer.FUNCTION:
+		return true
+	case sqllexer.UNKNOWN, sqllexer.PUNCTUATION:
+		return val == "`" || val == "["
+	default:
+		return false
+	}
+}
+
+// rescanName re-reads a possibly qualified name like db.schema."Table".col
+// as one identifier token. The lexer splits such names at quoted parts,
+// breaks "a""b" and "first name" apart, and swallows characters like '/'
+// and '!' that follow a name.
+func (ts *TokenStream) rescanName() {
+	end, ok := scanNamePart(ts.query, ts.currentPos)
+	if !ok {
+		return
+	}
+
+	for end < len(ts.query) && ts.query[end] == '.' {
+		next, ok := scanNamePart(ts.query, end+1)
+		if !ok {
+			// keep the dot of a qualified wildcard, which the select list
+			// parser expects as "u." followed by "*"
+			if end+1 < len(ts.query) && ts.query[end+1] == '*' {
+				end++
+			}
+			break
+		}
+		end = next
+	}
+
+	ts.currentType = sqllexer.IDENT
+	if end < len(ts.query) && ts.query[end] == '(' {
+		ts.currentType = sqllexer.FUNCTION
+	}
+
+	if end == ts.currentPos+len(ts.currentVal) {
+		return
+	}
+	ts.currentVal = ts.query[ts.currentPos:end]
+	ts.offset = end
+	ts.lexer = sqllexer.New(ts.query[ts.offset:])
+}
+
+// joinLeadingDot reads a number written without an integer part, like .5,
+// as one token. The lexer only does so after a sign and otherwise returns
+// the dot and the digits separately.
+func (ts *TokenStream) joinLeadingDot() {
+	if ts.offset >= len(ts.query) || ts.query[ts.offset] < '0' || ts.query[ts.offset] > '9' {
+		return
+	}
+
+	t := ts.lexer.Scan()
+	ts.currentType = sqllexer.NUMBER
+	ts.currentVal += t.Value
+	ts.offset += len(t.Value)
+}
+
+// splitGluedToken undoes places where the lexer merges what the parser needs
+// as separate tokens: operator runs like "=-" in "a=-1" are scanned as a
+// single operator.
+func (ts *TokenStream) splitGluedToken() {
+	if ts.currentType != sqllexer.OPERATOR {
+		return
+	}
+	if _, ok := operators[ts.currentVal]; ok {
+		return
+	}
+
+	for i := len(ts.currentVal) - 1; i > 0; i-- {
+		if _, ok := operators[ts.currentVal[:i]]; ok {
+			ts.truncateCurrent(i)
+			return
+		}
+	}
+}
+
+// truncateCurrent shortens the current token to its first n bytes and
+// restarts the lexer right after them.
+func (ts *TokenStream) truncateCurrent(n int) {
+	ts.currentVal = ts.currentVal[:n]
+	ts.offset = ts.currentPos + n
+	ts.lexer = sqllexer.New(ts.query[ts.offset:])
+}
+
+// Lookahead returns a separate stream positioned at the current token, so
+// that the tokens ahead can be inspected without consuming them.
+func (ts *TokenStream) Lookahead() *TokenStream {
+	ts.Initialize()
+	ahead := NewTokenStream(ts.query[ts.currentPos:])
+	ahead.Initialize()
+	return ahead
+}
+
+// SplitSign turns a signed number such as "-1" into a separate sign
+// operator followed by the unsigned number. The lexer always attaches a
+// leading sign to a number, which is wrong when the sign is a binary
+// operator, as in "a -1".
+func (ts *TokenStream) SplitSign() bool {
+	tokenType, val := ts.Current()
+	if tokenType != sqllexer.NUMBER || len(val) < 2 || (val[0] != '+' && val[0] != '-') {
+		return false
+	}
+
+	ts.currentType = sqllexer.OPERATOR
+	ts.truncateCurrent(1)
+	return true
 }
 
 func (ts *TokenStream) Current() (sqllexer.TokenType, string) {
@@ -89,9 +260,11 @@ func (ts *TokenStream) Consume(expected string) error {
 	return nil
 }
 
+// ConsumeIdentifier consumes a name. The lexer reports a name directly
+// followed by "(", as in "t(a, b)", as a function, so that is accepted too.
 func (ts *TokenStream) ConsumeIdentifier() (string, error) {
 	tokenType, val := ts.Current()
-	if tokenType != sqllexer.IDENT {
+	if tokenType != sqllexer.IDENT && tokenType != sqllexer.FUNCTION {
 		return "", fmt.Errorf("%w: expected identifier, got %q", ErrSyntaxError, val)
 	}
 
@@ -127,23 +300,63 @@ func (ts *TokenStream) IsEOF() bool {
 	return tokenType == sqllexer.EOF
 }
 
-func ParseQuery(query string) (ast.Wrapper, error) {
+// ParseQuery parses the first statement of query. Anything after the
+// semicolon that ends it is ignored; use ParseScript to parse them all.
+func ParseQuery(query string) (ast.Statement, error) {
 	ts := NewTokenStream(query)
 	ts.Initialize()
 
+	return parseStatement(ts)
+}
+
+// parseStatement parses the statement starting at the current token and
+// records its source span. It stops at the terminating semicolon, if any.
+func parseStatement(ts *TokenStream) (ast.Statement, error) {
 	_, val := ts.Current()
 	upperVal := strings.ToUpper(val)
+	start := ts.currentPos
+	ts.positionalParams = 0
+
+	var stmt ast.Statement
+	var err error
 
 	// Determine the statement type based on the first token
-	if upperVal == T_SELECT {
-		return parseSelectStatement(ts)
+	if upperVal == T_SELECT || upperVal == T_WITH || upperVal == T_LPAREN {
+		stmt, err = parseQuery(ts)
+		if err == nil {
+			err = expectStatementEnd(ts, T_SELECT)
+		}
 	} else if upperVal == T_CREATE {
-		return parseCreateTableStatement(ts)
+		stmt, err = parseCreateTableStatement(ts)
 	} else if upperVal == T_INSERT {
-		return parseInsertStatement(ts)
+		stmt, err = parseInsertStatement(ts)
+	} else if upperVal == T_UPDATE {
+		stmt, err = parseUpdateStatement(ts)
+	} else if upperVal == T_DELETE {
+		stmt, err = parseDeleteStatement(ts)
+	} else if upperVal == T_TRUNCATE {
+		stmt, err = parseTruncateStatement(ts)
+	} else if upperVal == T_MERGE {
+		stmt, err = parseMergeStatement(ts)
 	} else {
 		return nil, fmt.Errorf("%w: unsupported statement type: %q", ErrSyntaxError, val)
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	stmt.SetSourceSpan(ast.Span{Start: start, End: ts.prevEnd})
+	return stmt, nil
+}
+
+// expectStatementEnd checks that only an optional semicolon follows a
+// complete statement.
+func expectStatementEnd(ts *TokenStream, statement string) error {
+	_, val := ts.Current()
+	if !ts.IsEOF() && val != T_SEMICOLON {
+		return fmt.Errorf("%w: unexpected token after %s statement", ErrSyntaxError, statement)
+	}
+	return nil
 }
 
 func QueryToAst(query string) (ast.Wrapper, error) {
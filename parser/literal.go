@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/go-sqllexer"
+
+	"cockatoo/ast"
+)
+
+// isLiteralToken reports whether the token starts a literal value.
+func isLiteralToken(tokenType sqllexer.TokenType) bool {
+	switch tokenType {
+	case sqllexer.NUMBER, sqllexer.STRING, sqllexer.BOOLEAN, sqllexer.NULL:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseLiteral parses a single literal value. It is shared by every clause
+// that accepts constants so that they all agree on how values are read.
+func parseLiteral(ts *TokenStream) (ast.Expr, error) {
+	tokenType, val := ts.Current()
+
+	switch tokenType {
+	case sqllexer.NUMBER:
+		literal, err := parseNumber(val)
+		if err != nil {
+			return nil, err
+		}
+		ts.Next()
+		return literal, nil
+	case sqllexer.STRING:
+		ts.Next()
+		return &ast.LiteralString{Value: unquoteString(val)}, nil
+	case sqllexer.BOOLEAN:
+		ts.Next()
+		return &ast.LiteralBool{Value: strings.ToUpper(val) == T_TRUE}, nil
+	case sqllexer.NULL:
+		ts.Next()
+		return &ast.LiteralNull{}, nil
+	default:
+		return nil, fmt.Errorf("%w: expected value, got %q", ErrSyntaxError, val)
+	}
+}
+
+// parseNumber classifies a numeric token: integers that fit in int64 become
+// LiteralInt, values with an exponent become LiteralFloat, and everything
+// else (fractions, out of range integers) keeps its exact text as a
+// LiteralDecimal.
+func parseNumber(val string) (ast.Expr, error) {
+	digits := strings.TrimLeft(val, "+-")
+
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		intVal, err := strconv.ParseInt(val, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid hexadecimal number %q", ErrSyntaxError, val)
+		}
+		return &ast.LiteralInt{Value: intVal}, nil
+	}
+
+	if strings.ContainsAny(digits, "eE") {
+		floatVal, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid number %q", ErrSyntaxError, val)
+		}
+		return &ast.LiteralFloat{Value: floatVal}, nil
+	}
+
+	if strings.Count(digits, ".") > 1 || digits == "." {
+		return nil, fmt.Errorf("%w: invalid number %q", ErrSyntaxError, val)
+	}
+
+	if !strings.Contains(digits, ".") {
+		if intVal, err := strconv.ParseInt(val, 10, 64); err == nil {
+			return &ast.LiteralInt{Value: intVal}, nil
+		}
+	}
+
+	return &ast.LiteralDecimal{Value: strings.TrimPrefix(val, "+")}, nil
+}
+
+// unquoteString strips the surrounding quotes from a string token and
+// collapses each doubled quote into one, as the SQL standard requires.
+func unquoteString(val string) string {
+	if len(val) < 2 {
+		return val
+	}
+	return strings.ReplaceAll(val[1:len(val)-1], "''", "'")
+}
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"cockatoo/ast"
+)
+
+// parseMergeStatement parses MERGE INTO target [[AS] alias] USING source
+// ON condition followed by one or more WHEN clauses.
+func parseMergeStatement(ts *TokenStream) (*ast.MergeStmt, error) {
+	if err := ts.Consume(T_MERGE); err != nil {
+		return nil, err
+	}
+
+	if err := ts.Consume(T_INTO); err != nil {
+		return nil, err
+	}
+
+	target, err := parseTableName(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ast.MergeStmt{
+		Target: target,
+	}
+
+	if err := ts.Consume(T_USING); err != nil {
+		return nil, err
+	}
+
+	source, err := parseTablePrimary(ts)
+	if err != nil {
+		return nil, err
+	}
+	result.Source = source
+
+	if err := ts.Consume(T_ON); err != nil {
+		return nil, err
+	}
+
+	on, err := parseExpression(ts)
+	if err != nil {
+		return nil, err
+	}
+	result.On = on
+
+	for {
+		if _, val := ts.Current(); strings.ToUpper(val) != T_WHEN {
+			break
+		}
+
+		when, err := parseMergeWhen(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Whens = append(result.Whens, when)
+	}
+
+	if len(result.Whens) == 0 {
+		return nil, fmt.Errorf("%w: MERGE requires at least one WHEN clause", ErrSyntaxError)
+	}
+
+	if err := expectStatementEnd(ts, T_MERGE); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseMergeWhen parses WHEN [NOT] MATCHED [AND condition] THEN followed by
+// UPDATE SET ..., DELETE or DO NOTHING for matched rows, and INSERT ... or
+// DO NOTHING for rows that did not match.
+func parseMergeWhen(ts *TokenStream) (ast.MergeWhen, error) {
+	if err := ts.Consume(T_WHEN); err != nil {
+		return ast.MergeWhen{}, err
+	}
+
+	result := ast.MergeWhen{Matched: true}
+	if _, val := ts.Current(); strings.ToUpper(val) == T_NOT {
+		ts.Next()
+		result.Matched = false
+	}
+
+	if err := ts.Consume(T_MATCHED); err != nil {
+		return ast.MergeWhen{}, err
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_AND {
+		ts.Next()
+
+		condition, err := parseExpression(ts)
+		if err != nil {
+			return ast.MergeWhen{}, err
+		}
+		result.Condition = condition
+	}
+
+	if err := ts.Consume(T_THEN); err != nil {
+		return ast.MergeWhen{}, err
+	}
+
+	_, val := ts.Current()
+	action := strings.ToUpper(val)
+	switch {
+	case action == T_DO:
+		ts.Next()
+		if err := ts.Consume(T_NOTHING); err != nil {
+			return ast.MergeWhen{}, err
+		}
+		result.Action = T_NOTHING
+	case action == T_UPDATE && result.Matched:
+		ts.Next()
+		if err := ts.Consume(T_SET); err != nil {
+			return ast.MergeWhen{}, err
+		}
+
+		set, err := parseAssignmentList(ts)
+		if err != nil {
+			return ast.MergeWhen{}, err
+		}
+		result.Action = T_UPDATE
+		result.Set = set
+	case action == T_DELETE && result.Matched:
+		ts.Next()
+		result.Action = T_DELETE
+	case action == T_INSERT && !result.Matched:
+		ts.Next()
+		result.Action = T_INSERT
+
+		if err := parseMergeInsert(ts, &result); err != nil {
+			return ast.MergeWhen{}, err
+		}
+	case result.Matched:
+		return ast.MergeWhen{}, fmt.Errorf("%w: expected UPDATE, DELETE or DO NOTHING after WHEN MATCHED THEN, got %q", ErrSyntaxError, val)
+	default:
+		return ast.MergeWhen{}, fmt.Errorf("%w: expected INSERT or DO NOTHING after WHEN NOT MATCHED THEN, got %q", ErrSyntaxError, val)
+	}
+
+	return result, nil
+}
+
+// parseMergeInsert parses the rest of the INSERT action of a MERGE:
+// [(columns)] VALUES (row) or DEFAULT VALUES.
+func parseMergeInsert(ts *TokenStream, when *ast.MergeWhen) error {
+	if _, val := ts.Current(); val == T_LPAREN {
+		columns, err := parseIdentifierList(ts)
+		if err != nil {
+			return err
+		}
+		when.Columns = columns
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_DEFAULT {
+		if when.Columns != nil {
+			return fmt.Errorf("%w: DEFAULT VALUES cannot follow a column list", ErrSyntaxError)
+		}
+		ts.Next()
+		if err := ts.Consume(T_VALUES); err != nil {
+			return err
+		}
+		when.DefaultValues = true
+		return nil
+	}
+
+	if err := ts.Consume(T_VALUES); err != nil {
+		return err
+	}
+
+	rows, err := parseValuesRows(ts, len(when.Columns))
+	if err != nil {
+		return err
+	}
+	if len(rows) != 1 {
+		return fmt.Errorf("%w: MERGE INSERT takes a single VALUES row", ErrSyntaxError)
+	}
+	when.Values = rows[0]
+
+	return nil
+}
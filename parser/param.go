@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/go-sqllexer"
+
+	"cockatoo/ast"
+)
+
+// isParamStart reports whether the current token begins a bind parameter:
+// $1, ?, :name or @name.
+func isParamStart(ts *TokenStream) bool {
+	tokenType, val := ts.Current()
+
+	switch tokenType {
+	case sqllexer.POSITIONAL_PARAMETER, sqllexer.BIND_PARAMETER:
+		return true
+	case sqllexer.OPERATOR:
+		return val == T_QUESTION || val == T_COLON
+	default:
+		return false
+	}
+}
+
+// parseParam parses a bind parameter. The lexer reads :name as the
+// operator ":" followed by a word, which must follow without space and may
+// be a keyword, as in ":limit".
+func parseParam(ts *TokenStream) (*ast.Param, error) {
+	tokenType, val := ts.Current()
+	pos := ts.currentPos
+
+	switch {
+	case tokenType == sqllexer.POSITIONAL_PARAMETER:
+		index, err := strconv.Atoi(strings.TrimPrefix(val, "$"))
+		if err != nil || index < 1 {
+			return nil, fmt.Errorf("%w: invalid parameter %s", ErrSyntaxError, val)
+		}
+		ts.Next()
+
+		return &ast.Param{Kind: "positional", Marker: "$", Index: index, Pos: pos}, nil
+	case tokenType == sqllexer.BIND_PARAMETER:
+		ts.Next()
+
+		return &ast.Param{Kind: "named", Marker: "@", Name: strings.TrimPrefix(val, "@"), Pos: pos}, nil
+	case val == T_QUESTION:
+		ts.Next()
+		ts.positionalParams++
+
+		return &ast.Param{Kind: "positional", Marker: "?", Index: ts.positionalParams, Pos: pos}, nil
+	}
+
+	end := ts.currentPos + len(val)
+	ts.Next()
+
+	tokenType, name := ts.Current()
+	isWord := tokenType == sqllexer.IDENT || tokenType == sqllexer.KEYWORD || tokenType == sqllexer.COMMAND
+	if !isWord || ts.currentPos != end {
+		return nil, fmt.Errorf("%w: expected parameter name after :", ErrSyntaxError)
+	}
+	ts.Next()
+
+	return &ast.Param{Kind: "named", Marker: ":", Name: name, Pos: pos}, nil
+}
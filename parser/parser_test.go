@@ -21,7 +21,7 @@ func TestSelectQueries(t *testing.T) {
 				Projections: []ast.ProjectionItem{
 					{IsWildcard: true},
 				},
-				From: ast.TableRef{Name: "users"},
+				From: &ast.TableRef{Name: "users"},
 			},
 			wantErr: false,
 		},
@@ -33,7 +33,7 @@ func TestSelectQueries(t *testing.T) {
 					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
 					{Expression: &ast.ColumnRef{Name: "name"}, IsWildcard: false},
 				},
-				From: ast.TableRef{Name: "users"},
+				From: &ast.TableRef{Name: "users"},
 			},
 			wantErr: false,
 		},
@@ -44,7 +44,7 @@ func TestSelectQueries(t *testing.T) {
 				Projections: []ast.ProjectionItem{
 					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
 				},
-				From: ast.TableRef{Name: "users"},
+				From: &ast.TableRef{Name: "users"},
 				Selection: &ast.ComparisonOp{
 					Left:     &ast.ColumnRef{Name: "age"},
 					Operator: ">",
@@ -60,13 +60,1699 @@ func TestSelectQueries(t *testing.T) {
 				Projections: []ast.ProjectionItem{
 					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
 				},
-				From: ast.TableRef{Name: "users"},
+				From: &ast.TableRef{Name: "users"},
 				Selection: &ast.ComparisonOp{
 					Left:     &ast.ColumnRef{Name: "age"},
 					Operator: ">",
 					Right:    &ast.LiteralInt{Value: 18},
 				},
-				Limit: &[]uint64{10}[0],
+				Limit: &ast.LiteralInt{Value: 10},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with and binding tighter than or",
+			query: "SELECT id FROM users WHERE x = 1 AND y = 2 OR z = 3",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.LogicalOp{
+						Left:     &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "x"}, Operator: "=", Right: &ast.LiteralInt{Value: 1}},
+						Operator: "AND",
+						Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "y"}, Operator: "=", Right: &ast.LiteralInt{Value: 2}},
+					},
+					Operator: "OR",
+					Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "z"}, Operator: "=", Right: &ast.LiteralInt{Value: 3}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with parenthesized or group",
+			query: "SELECT id FROM users WHERE (a = 1 OR b = 2) AND c = 3",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.LogicalOp{
+						Left:     &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "a"}, Operator: "=", Right: &ast.LiteralInt{Value: 1}},
+						Operator: "OR",
+						Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "b"}, Operator: "=", Right: &ast.LiteralInt{Value: 2}},
+					},
+					Operator: "AND",
+					Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "c"}, Operator: "=", Right: &ast.LiteralInt{Value: 3}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with not",
+			query: "SELECT id FROM users WHERE NOT (a = 1 OR b = 2) AND NOT c = 3",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.UnaryOp{
+						Operator: "NOT",
+						Operand: &ast.LogicalOp{
+							Left:     &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "a"}, Operator: "=", Right: &ast.LiteralInt{Value: 1}},
+							Operator: "OR",
+							Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "b"}, Operator: "=", Right: &ast.LiteralInt{Value: 2}},
+						},
+					},
+					Operator: "AND",
+					Right: &ast.UnaryOp{
+						Operator: "NOT",
+						Operand:  &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "c"}, Operator: "=", Right: &ast.LiteralInt{Value: 3}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with arithmetic in projection and where",
+			query: "SELECT price * qty FROM orders WHERE price * qty > 100 + discount",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.BinaryOp{Left: &ast.ColumnRef{Name: "price"}, Operator: "*", Right: &ast.ColumnRef{Name: "qty"}},
+						IsWildcard: false,
+					},
+				},
+				From: &ast.TableRef{Name: "orders"},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.BinaryOp{Left: &ast.ColumnRef{Name: "price"}, Operator: "*", Right: &ast.ColumnRef{Name: "qty"}},
+					Operator: ">",
+					Right:    &ast.BinaryOp{Left: &ast.LiteralInt{Value: 100}, Operator: "+", Right: &ast.ColumnRef{Name: "discount"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with string concatenation",
+			query: "SELECT first || ' ' || last FROM users",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.BinaryOp{
+							Left:     &ast.BinaryOp{Left: &ast.ColumnRef{Name: "first"}, Operator: "||", Right: &ast.LiteralString{Value: " "}},
+							Operator: "||",
+							Right:    &ast.ColumnRef{Name: "last"},
+						},
+						IsWildcard: false,
+					},
+				},
+				From: &ast.TableRef{Name: "users"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with unary minus and unspaced operators",
+			query: "SELECT -a FROM t WHERE b-1 >= c/2 % 3",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.UnaryOp{Operator: "-", Operand: &ast.ColumnRef{Name: "a"}}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "t"},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.BinaryOp{Left: &ast.ColumnRef{Name: "b"}, Operator: "-", Right: &ast.LiteralInt{Value: 1}},
+					Operator: ">=",
+					Right: &ast.BinaryOp{
+						Left:     &ast.BinaryOp{Left: &ast.ColumnRef{Name: "c"}, Operator: "/", Right: &ast.LiteralInt{Value: 2}},
+						Operator: "%",
+						Right:    &ast.LiteralInt{Value: 3},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with literals of every kind",
+			query: "SELECT id FROM t WHERE a = 3.14 OR b = 1e6 OR c = -5 OR d = TRUE OR e = 'O''Brien'",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "t"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.LogicalOp{
+						Left: &ast.LogicalOp{
+							Left: &ast.LogicalOp{
+								Left:     &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "a"}, Operator: "=", Right: &ast.LiteralDecimal{Value: "3.14"}},
+								Operator: "OR",
+								Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "b"}, Operator: "=", Right: &ast.LiteralFloat{Value: 1e6}},
+							},
+							Operator: "OR",
+							Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "c"}, Operator: "=", Right: &ast.LiteralInt{Value: -5}},
+						},
+						Operator: "OR",
+						Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "d"}, Operator: "=", Right: &ast.LiteralBool{Value: true}},
+					},
+					Operator: "OR",
+					Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "e"}, Operator: "=", Right: &ast.LiteralString{Value: "O'Brien"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with numbers without an integer part",
+			query: "SELECT .5, -.25, a * .5e2 FROM t",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.LiteralDecimal{Value: ".5"}, IsWildcard: false},
+					{Expression: &ast.LiteralDecimal{Value: "-.25"}, IsWildcard: false},
+					{Expression: &ast.BinaryOp{Left: &ast.ColumnRef{Name: "a"}, Operator: "*", Right: &ast.LiteralFloat{Value: 50}}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "t"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with function calls and aggregates",
+			query: "SELECT COUNT(*), count(DISTINCT city), lower(name) FROM users WHERE coalesce(a, b) > now()",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.FuncCall{Name: "COUNT", Star: true}, IsWildcard: false},
+					{Expression: &ast.FuncCall{Name: "count", Args: []ast.Expr{&ast.ColumnRef{Name: "city"}}, Distinct: true}, IsWildcard: false},
+					{Expression: &ast.FuncCall{Name: "lower", Args: []ast.Expr{&ast.ColumnRef{Name: "name"}}}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.FuncCall{Name: "coalesce", Args: []ast.Expr{&ast.ColumnRef{Name: "a"}, &ast.ColumnRef{Name: "b"}}},
+					Operator: ">",
+					Right:    &ast.FuncCall{Name: "now"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with both not equal operators",
+			query: "SELECT id FROM t WHERE a <> 1 AND b != 2 AND c<>-1",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "t"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.LogicalOp{
+						Left:     &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "a"}, Operator: "<>", Right: &ast.LiteralInt{Value: 1}},
+						Operator: "AND",
+						Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "b"}, Operator: "!=", Right: &ast.LiteralInt{Value: 2}},
+					},
+					Operator: "AND",
+					Right:    &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "c"}, Operator: "<>", Right: &ast.LiteralInt{Value: -1}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with functions named by keywords",
+			query: "SELECT LEFT(s, 2), right(s, 1) FROM t",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.FuncCall{Name: "LEFT", Args: []ast.Expr{&ast.ColumnRef{Name: "s"}, &ast.LiteralInt{Value: 2}}}, IsWildcard: false},
+					{Expression: &ast.FuncCall{Name: "right", Args: []ast.Expr{&ast.ColumnRef{Name: "s"}, &ast.LiteralInt{Value: 1}}}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "t"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with aliases and qualified wildcard",
+			query: "SELECT price * 2 AS doubled, u.name n, u.* FROM users AS u",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.BinaryOp{Left: &ast.ColumnRef{Name: "price"}, Operator: "*", Right: &ast.LiteralInt{Value: 2}},
+						Alias:      ast.Identifier{Value: "doubled"},
+						IsWildcard: false,
+					},
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "u"}}, Name: "name"}, Alias: ast.Identifier{Value: "n"}, IsWildcard: false},
+					{IsWildcard: true, Qualifier: []ast.Identifier{{Value: "u"}}},
+				},
+				From: &ast.TableRef{Name: "users", Alias: ast.Identifier{Value: "u"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with bare table alias",
+			query: "SELECT o.id FROM orders o WHERE o.total > 10",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "o"}}, Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "orders", Alias: ast.Identifier{Value: "o"}},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "o"}}, Name: "total"},
+					Operator: ">",
+					Right:    &ast.LiteralInt{Value: 10},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with comma and explicit joins",
+			query: "SELECT u.name FROM users u, accounts a JOIN orders o ON a.id = o.account_id",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "u"}}, Name: "name"}, IsWildcard: false},
+				},
+				From: &ast.Join{
+					Type: "CROSS",
+					Left: &ast.TableRef{Name: "users", Alias: ast.Identifier{Value: "u"}},
+					Right: &ast.Join{
+						Type:  "INNER",
+						Left:  &ast.TableRef{Name: "accounts", Alias: ast.Identifier{Value: "a"}},
+						Right: &ast.TableRef{Name: "orders", Alias: ast.Identifier{Value: "o"}},
+						On: &ast.ComparisonOp{
+							Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "a"}}, Name: "id"},
+							Operator: "=",
+							Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "o"}}, Name: "account_id"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with outer, natural and cross joins",
+			query: "SELECT * FROM users LEFT OUTER JOIN orders USING (user_id) NATURAL JOIN profiles CROSS JOIN regions",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{IsWildcard: true},
+				},
+				From: &ast.Join{
+					Type: "CROSS",
+					Left: &ast.Join{
+						Type:    "INNER",
+						Natural: true,
+						Left: &ast.Join{
+							Type:  "LEFT",
+							Left:  &ast.TableRef{Name: "users"},
+							Right: &ast.TableRef{Name: "orders"},
+							Using: []ast.Identifier{{Value: "user_id"}},
+						},
+						Right: &ast.TableRef{Name: "profiles"},
+					},
+					Right: &ast.TableRef{Name: "regions"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with parenthesized join",
+			query: "SELECT * FROM (a JOIN b ON a.id = b.id) FULL JOIN c ON c.id = a.id",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{IsWildcard: true},
+				},
+				From: &ast.Join{
+					Type: "FULL",
+					Left: &ast.Join{
+						Type:  "INNER",
+						Left:  &ast.TableRef{Name: "a"},
+						Right: &ast.TableRef{Name: "b"},
+						On:    &ast.ComparisonOp{Left: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "a"}}, Name: "id"}, Operator: "=", Right: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "b"}}, Name: "id"}},
+					},
+					Right: &ast.TableRef{Name: "c"},
+					On:    &ast.ComparisonOp{Left: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "c"}}, Name: "id"}, Operator: "=", Right: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "a"}}, Name: "id"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with aliased parenthesized join",
+			query: "SELECT x.id FROM (t JOIN s ON t.id = s.id) AS x",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "x"}}, Name: "id"}},
+				},
+				From: &ast.Join{
+					Type:  "INNER",
+					Left:  &ast.TableRef{Name: "t"},
+					Right: &ast.TableRef{Name: "s"},
+					On:    &ast.ComparisonOp{Left: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "t"}}, Name: "id"}, Operator: "=", Right: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "id"}},
+					Alias: ast.Identifier{Value: "x"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with group by and having",
+			query: "SELECT city, COUNT(*) FROM users WHERE age > 18 GROUP BY city HAVING COUNT(*) > 10 LIMIT 5",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "city"}, IsWildcard: false},
+					{Expression: &ast.FuncCall{Name: "COUNT", Star: true}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.ColumnRef{Name: "age"},
+					Operator: ">",
+					Right:    &ast.LiteralInt{Value: 18},
+				},
+				GroupBy: []ast.Expr{&ast.ColumnRef{Name: "city"}},
+				Having: &ast.ComparisonOp{
+					Left:     &ast.FuncCall{Name: "COUNT", Star: true},
+					Operator: ">",
+					Right:    &ast.LiteralInt{Value: 10},
+				},
+				Limit: &ast.LiteralInt{Value: 5},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with rollup, cube and grouping sets",
+			query: "SELECT a FROM t GROUP BY a, ROLLUP(b, (c, d)), GROUPING SETS ((a, b), (), CUBE(c))",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "a"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "t"},
+				GroupBy: []ast.Expr{
+					&ast.ColumnRef{Name: "a"},
+					&ast.GroupingSet{
+						Type: "ROLLUP",
+						Sets: [][]ast.Expr{
+							{&ast.ColumnRef{Name: "b"}},
+							{&ast.ColumnRef{Name: "c"}, &ast.ColumnRef{Name: "d"}},
+						},
+					},
+					&ast.GroupingSet{
+						Type: "GROUPING SETS",
+						Sets: [][]ast.Expr{
+							{&ast.ColumnRef{Name: "a"}, &ast.ColumnRef{Name: "b"}},
+							{},
+							{&ast.GroupingSet{Type: "CUBE", Sets: [][]ast.Expr{{&ast.ColumnRef{Name: "c"}}}}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with order by, offset and fetch",
+			query: "SELECT id FROM posts ORDER BY created_at DESC NULLS LAST, id OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "posts"},
+				OrderBy: []ast.OrderByItem{
+					{Expression: &ast.ColumnRef{Name: "created_at"}, Direction: "DESC", Nulls: "LAST"},
+					{Expression: &ast.ColumnRef{Name: "id"}},
+				},
+				Offset: &ast.LiteralInt{Value: 20},
+				Fetch:  &ast.Fetch{Count: &ast.LiteralInt{Value: 10}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with limit all and offset",
+			query: "SELECT id FROM posts ORDER BY id ASC LIMIT ALL OFFSET 5",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "posts"},
+				OrderBy: []ast.OrderByItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, Direction: "ASC"},
+				},
+				LimitAll: true,
+				Offset:   &ast.LiteralInt{Value: 5},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with offset before limit",
+			query: "SELECT id FROM posts ORDER BY id OFFSET 20 LIMIT 10",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "posts"},
+				OrderBy: []ast.OrderByItem{
+					{Expression: &ast.ColumnRef{Name: "id"}},
+				},
+				Limit:  &ast.LiteralInt{Value: 10},
+				Offset: &ast.LiteralInt{Value: 20},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with fetch next row with ties",
+			query: "SELECT id FROM scores ORDER BY points DESC FETCH NEXT ROW WITH TIES",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}, IsWildcard: false},
+				},
+				From: &ast.TableRef{Name: "scores"},
+				OrderBy: []ast.OrderByItem{
+					{Expression: &ast.ColumnRef{Name: "points"}, Direction: "DESC"},
+				},
+				Fetch: &ast.Fetch{Count: &ast.LiteralInt{Value: 1}, WithTies: true},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with scalar subquery and in subquery",
+			query: "SELECT (SELECT max(total) FROM orders) AS top FROM users WHERE id NOT IN (SELECT user_id FROM bans)",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.Subquery{Query: &ast.SelectStmt{
+							Projections: []ast.ProjectionItem{
+								{Expression: &ast.FuncCall{Name: "max", Args: []ast.Expr{&ast.ColumnRef{Name: "total"}}}},
+							},
+							From: &ast.TableRef{Name: "orders"},
+						}},
+						Alias: ast.Identifier{Value: "top"},
+					},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.InSubquery{
+					Expression: &ast.ColumnRef{Name: "id"},
+					Query: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "user_id"}}},
+						From:        &ast.TableRef{Name: "bans"},
+					},
+					Not: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with exists and quantified subqueries",
+			query: "SELECT id FROM t WHERE EXISTS (SELECT 1 FROM u WHERE u.id = t.id) AND score > ALL (SELECT score FROM v)",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}},
+				},
+				From: &ast.TableRef{Name: "t"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.Exists{Query: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.LiteralInt{Value: 1}}},
+						From:        &ast.TableRef{Name: "u"},
+						Selection:   &ast.ComparisonOp{Left: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "u"}}, Name: "id"}, Operator: "=", Right: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "t"}}, Name: "id"}},
+					}},
+					Operator: "AND",
+					Right: &ast.ComparisonOp{
+						Left:     &ast.ColumnRef{Name: "score"},
+						Operator: ">",
+						Right: &ast.QuantifiedSubquery{
+							Quantifier: "ALL",
+							Query: &ast.SelectStmt{
+								Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "score"}}},
+								From:        &ast.TableRef{Name: "v"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select from derived table",
+			query: "SELECT r.n FROM (SELECT COUNT(*) AS n FROM orders LIMIT 1) AS r",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "r"}}, Name: "n"}},
+				},
+				From: &ast.DerivedTable{
+					Query: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.FuncCall{Name: "COUNT", Star: true}, Alias: ast.Identifier{Value: "n"}}},
+						From:        &ast.TableRef{Name: "orders"},
+						Limit:       &ast.LiteralInt{Value: 1},
+					},
+					Alias: ast.Identifier{Value: "r"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select from doubly parenthesized derived table",
+			query: "SELECT a FROM ((SELECT a FROM t)) s",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "a"}},
+				},
+				From: &ast.DerivedTable{
+					Query: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+						From:        &ast.TableRef{Name: "t"},
+					},
+					Alias: ast.Identifier{Value: "s"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with in list, between and is null",
+			query: "SELECT id FROM users WHERE status IN ('a', 'b') AND age NOT BETWEEN 18 AND 65 AND deleted_at IS NULL",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.LogicalOp{
+						Left: &ast.InList{
+							Expression: &ast.ColumnRef{Name: "status"},
+							List:       []ast.Expr{&ast.LiteralString{Value: "a"}, &ast.LiteralString{Value: "b"}},
+						},
+						Operator: "AND",
+						Right: &ast.Between{
+							Expression: &ast.ColumnRef{Name: "age"},
+							Low:        &ast.LiteralInt{Value: 18},
+							High:       &ast.LiteralInt{Value: 65},
+							Not:        true,
+						},
+					},
+					Operator: "AND",
+					Right:    &ast.IsNull{Expression: &ast.ColumnRef{Name: "deleted_at"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with like escape, ilike and is not null",
+			query: "SELECT id FROM users WHERE name LIKE 'J%' ESCAPE '\\' OR email NOT ILIKE '%@test' OR NOT phone IS NOT NULL",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}},
+				},
+				From: &ast.TableRef{Name: "users"},
+				Selection: &ast.LogicalOp{
+					Left: &ast.LogicalOp{
+						Left: &ast.Like{
+							Expression: &ast.ColumnRef{Name: "name"},
+							Pattern:    &ast.LiteralString{Value: "J%"},
+							Escape:     &ast.LiteralString{Value: "\\"},
+							Operator:   "LIKE",
+						},
+						Operator: "OR",
+						Right: &ast.Like{
+							Expression: &ast.ColumnRef{Name: "email"},
+							Pattern:    &ast.LiteralString{Value: "%@test"},
+							Operator:   "ILIKE",
+							Not:        true,
+						},
+					},
+					Operator: "OR",
+					Right: &ast.UnaryOp{
+						Operator: "NOT",
+						Operand:  &ast.IsNull{Expression: &ast.ColumnRef{Name: "phone"}, Not: true},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with searched and simple case",
+			query: "SELECT CASE WHEN age < 18 THEN 'minor' ELSE 'adult' END AS bracket, CASE status WHEN 1 THEN 'on' WHEN 0 THEN 'off' END FROM users",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.CaseExpr{
+							Whens: []ast.WhenClause{
+								{
+									Condition: &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "age"}, Operator: "<", Right: &ast.LiteralInt{Value: 18}},
+									Result:    &ast.LiteralString{Value: "minor"},
+								},
+							},
+							Else: &ast.LiteralString{Value: "adult"},
+						},
+						Alias: ast.Identifier{Value: "bracket"},
+					},
+					{
+						Expression: &ast.CaseExpr{
+							Operand: &ast.ColumnRef{Name: "status"},
+							Whens: []ast.WhenClause{
+								{Condition: &ast.LiteralInt{Value: 1}, Result: &ast.LiteralString{Value: "on"}},
+								{Condition: &ast.LiteralInt{Value: 0}, Result: &ast.LiteralString{Value: "off"}},
+							},
+						},
+					},
+				},
+				From: &ast.TableRef{Name: "users"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with cast and double colon conversion",
+			query: "SELECT CAST(price AS bigint), id::text || '-' FROM items WHERE -qty::int < 0",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.Cast{Expression: &ast.ColumnRef{Name: "price"}, Type: "BIGINT"}},
+					{
+						Expression: &ast.BinaryOp{
+							Left:     &ast.Cast{Expression: &ast.ColumnRef{Name: "id"}, Type: "TEXT"},
+							Operator: "||",
+							Right:    &ast.LiteralString{Value: "-"},
+						},
+					},
+				},
+				From: &ast.TableRef{Name: "items"},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.UnaryOp{Operator: "-", Operand: &ast.Cast{Expression: &ast.ColumnRef{Name: "qty"}, Type: "INT"}},
+					Operator: "<",
+					Right:    &ast.LiteralInt{Value: 0},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "union with intersect binding tighter and trailing order by",
+			query: "SELECT a FROM t UNION SELECT b FROM u INTERSECT ALL SELECT c FROM v ORDER BY 1 LIMIT 5",
+			expected: &ast.SetOperation{
+				Operator: "UNION",
+				Left: &ast.SelectStmt{
+					Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+					From:        &ast.TableRef{Name: "t"},
+				},
+				Right: &ast.SetOperation{
+					Operator: "INTERSECT",
+					All:      true,
+					Left: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "b"}}},
+						From:        &ast.TableRef{Name: "u"},
+					},
+					Right: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "c"}}},
+						From:        &ast.TableRef{Name: "v"},
+					},
+				},
+				OrderBy: []ast.OrderByItem{{Expression: &ast.LiteralInt{Value: 1}}},
+				Limit:   &ast.LiteralInt{Value: 5},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "set operations with parenthesized operands",
+			query: "(SELECT a FROM t ORDER BY a LIMIT 1) UNION ALL (SELECT b FROM u) EXCEPT DISTINCT SELECT c FROM w",
+			expected: &ast.SetOperation{
+				Operator: "EXCEPT",
+				Left: &ast.SetOperation{
+					Operator: "UNION",
+					All:      true,
+					Left: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+						From:        &ast.TableRef{Name: "t"},
+						OrderBy:     []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+						Limit:       &ast.LiteralInt{Value: 1},
+					},
+					Right: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "b"}}},
+						From:        &ast.TableRef{Name: "u"},
+					},
+				},
+				Right: &ast.SelectStmt{
+					Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "c"}}},
+					From:        &ast.TableRef{Name: "w"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "parenthesized query keeps its own clauses",
+			query: "(SELECT a FROM t LIMIT 1)",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+				From:        &ast.TableRef{Name: "t"},
+				Limit:       &ast.LiteralInt{Value: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "parenthesized query merged with later clauses",
+			query: "(SELECT a FROM t ORDER BY a) LIMIT 1",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+				From:        &ast.TableRef{Name: "t"},
+				OrderBy:     []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+				Limit:       &ast.LiteralInt{Value: 1},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "parenthesized query nested under clauses applied after its own",
+			query: "(SELECT a FROM t ORDER BY b LIMIT 10) ORDER BY a LIMIT 5",
+			expected: &ast.ParenQuery{
+				Query: &ast.SelectStmt{
+					Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+					From:        &ast.TableRef{Name: "t"},
+					OrderBy:     []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "b"}}},
+					Limit:       &ast.LiteralInt{Value: 10},
+				},
+				OrderBy: []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+				Limit:   &ast.LiteralInt{Value: 5},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "IN subquery starting with a parenthesized operand",
+			query: "SELECT x FROM r WHERE x IN ((SELECT a FROM t) UNION (SELECT b FROM s))",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "x"}}},
+				From:        &ast.TableRef{Name: "r"},
+				Selection: &ast.InSubquery{
+					Expression: &ast.ColumnRef{Name: "x"},
+					Query: &ast.SetOperation{
+						Operator: "UNION",
+						Left: &ast.SelectStmt{
+							Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+							From:        &ast.TableRef{Name: "t"},
+						},
+						Right: &ast.SelectStmt{
+							Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "b"}}},
+							From:        &ast.TableRef{Name: "s"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "scalar subquery starting with a parenthesized operand",
+			query: "SELECT ((SELECT a FROM t) UNION SELECT b FROM s) FROM r",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.Subquery{Query: &ast.SetOperation{
+					Operator: "UNION",
+					Left: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+						From:        &ast.TableRef{Name: "t"},
+					},
+					Right: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "b"}}},
+						From:        &ast.TableRef{Name: "s"},
+					},
+				}}}},
+				From: &ast.TableRef{Name: "r"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "parenthesized scalar subquery inside an expression",
+			query: "SELECT ((SELECT a FROM t) + 1) FROM r",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.BinaryOp{
+					Left: &ast.Subquery{Query: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+						From:        &ast.TableRef{Name: "t"},
+					}},
+					Operator: "+",
+					Right:    &ast.LiteralInt{Value: 1},
+				}}},
+				From: &ast.TableRef{Name: "r"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "derived table starting with a parenthesized operand",
+			query: "SELECT x.a FROM ((SELECT a FROM t) UNION SELECT b FROM s) x",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "x"}}, Name: "a"}}},
+				From: &ast.DerivedTable{
+					Query: &ast.SetOperation{
+						Operator: "UNION",
+						Left: &ast.SelectStmt{
+							Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}}},
+							From:        &ast.TableRef{Name: "t"},
+						},
+						Right: &ast.SelectStmt{
+							Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "b"}}},
+							From:        &ast.TableRef{Name: "s"},
+						},
+					},
+					Alias: ast.Identifier{Value: "x"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with common table expressions",
+			query: "WITH active AS (SELECT id FROM users WHERE active = TRUE), totals (user_id, total) AS (SELECT user_id, SUM(amount) FROM orders GROUP BY user_id) SELECT total FROM active JOIN totals ON active.id = totals.user_id",
+			expected: &ast.SelectStmt{
+				With: &ast.With{
+					CTEs: []ast.CommonTableExpr{
+						{
+							Name: ast.Identifier{Value: "active"},
+							Query: &ast.SelectStmt{
+								Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "id"}}},
+								From:        &ast.TableRef{Name: "users"},
+								Selection:   &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "active"}, Operator: "=", Right: &ast.LiteralBool{Value: true}},
+							},
+						},
+						{
+							Name:    ast.Identifier{Value: "totals"},
+							Columns: []ast.Identifier{{Value: "user_id"}, {Value: "total"}},
+							Query: &ast.SelectStmt{
+								Projections: []ast.ProjectionItem{
+									{Expression: &ast.ColumnRef{Name: "user_id"}},
+									{Expression: &ast.FuncCall{Name: "SUM", Args: []ast.Expr{&ast.ColumnRef{Name: "amount"}}}},
+								},
+								From:    &ast.TableRef{Name: "orders"},
+								GroupBy: []ast.Expr{&ast.ColumnRef{Name: "user_id"}},
+							},
+						},
+					},
+				},
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "total"}}},
+				From: &ast.Join{
+					Type:  "INNER",
+					Left:  &ast.TableRef{Name: "active"},
+					Right: &ast.TableRef{Name: "totals"},
+					On:    &ast.ComparisonOp{Left: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "active"}}, Name: "id"}, Operator: "=", Right: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "totals"}}, Name: "user_id"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with recursive common table expression",
+			query: "WITH RECURSIVE seq(n) AS (SELECT 1 FROM one UNION ALL SELECT n + 1 FROM seq WHERE n < 10) SELECT n FROM seq",
+			expected: &ast.SelectStmt{
+				With: &ast.With{
+					Recursive: true,
+					CTEs: []ast.CommonTableExpr{
+						{
+							Name:    ast.Identifier{Value: "seq"},
+							Columns: []ast.Identifier{{Value: "n"}},
+							Query: &ast.SetOperation{
+								Operator: "UNION",
+								All:      true,
+								Left: &ast.SelectStmt{
+									Projections: []ast.ProjectionItem{{Expression: &ast.LiteralInt{Value: 1}}},
+									From:        &ast.TableRef{Name: "one"},
+								},
+								Right: &ast.SelectStmt{
+									Projections: []ast.ProjectionItem{
+										{Expression: &ast.BinaryOp{Left: &ast.ColumnRef{Name: "n"}, Operator: "+", Right: &ast.LiteralInt{Value: 1}}},
+									},
+									From:      &ast.TableRef{Name: "seq"},
+									Selection: &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "n"}, Operator: "<", Right: &ast.LiteralInt{Value: 10}},
+								},
+							},
+						},
+					},
+				},
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "n"}}},
+				From:        &ast.TableRef{Name: "seq"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "parenthesized query with its own common table expression",
+			query: "(WITH x AS (SELECT 1 FROM t) SELECT * FROM x)",
+			expected: &ast.SelectStmt{
+				With: &ast.With{
+					CTEs: []ast.CommonTableExpr{
+						{
+							Name: ast.Identifier{Value: "x"},
+							Query: &ast.SelectStmt{
+								Projections: []ast.ProjectionItem{{Expression: &ast.LiteralInt{Value: 1}}},
+								From:        &ast.TableRef{Name: "t"},
+							},
+						},
+					},
+				},
+				Projections: []ast.ProjectionItem{{IsWildcard: true}},
+				From:        &ast.TableRef{Name: "x"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "common table expressions at two levels",
+			query: "WITH y AS (SELECT 2 FROM u) (WITH x AS (SELECT 1 FROM t) SELECT * FROM x)",
+			expected: &ast.ParenQuery{
+				With: &ast.With{
+					CTEs: []ast.CommonTableExpr{
+						{
+							Name: ast.Identifier{Value: "y"},
+							Query: &ast.SelectStmt{
+								Projections: []ast.ProjectionItem{{Expression: &ast.LiteralInt{Value: 2}}},
+								From:        &ast.TableRef{Name: "u"},
+							},
+						},
+					},
+				},
+				Query: &ast.SelectStmt{
+					With: &ast.With{
+						CTEs: []ast.CommonTableExpr{
+							{
+								Name: ast.Identifier{Value: "x"},
+								Query: &ast.SelectStmt{
+									Projections: []ast.ProjectionItem{{Expression: &ast.LiteralInt{Value: 1}}},
+									From:        &ast.TableRef{Name: "t"},
+								},
+							},
+						},
+					},
+					Projections: []ast.ProjectionItem{{IsWildcard: true}},
+					From:        &ast.TableRef{Name: "x"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with window function",
+			query: "SELECT row_number() OVER (PARTITION BY dept ORDER BY salary DESC) AS rn FROM employees",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.FuncCall{
+							Name: "row_number",
+							Over: &ast.WindowSpec{
+								PartitionBy: []ast.Expr{&ast.ColumnRef{Name: "dept"}},
+								OrderBy:     []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "salary"}, Direction: "DESC"}},
+							},
+						},
+						Alias: ast.Identifier{Value: "rn"},
+					},
+				},
+				From: &ast.TableRef{Name: "employees"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with window frame",
+			query: "SELECT sum(amount) OVER (ORDER BY day ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW), avg(amount) OVER (RANGE 3 PRECEDING) FROM sales",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{
+						Expression: &ast.FuncCall{
+							Name: "sum",
+							Args: []ast.Expr{&ast.ColumnRef{Name: "amount"}},
+							Over: &ast.WindowSpec{
+								OrderBy: []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "day"}}},
+								Frame: &ast.WindowFrame{
+									Units: "ROWS",
+									Start: ast.FrameBound{Type: "UNBOUNDED PRECEDING"},
+									End:   &ast.FrameBound{Type: "CURRENT ROW"},
+								},
+							},
+						},
+					},
+					{
+						Expression: &ast.FuncCall{
+							Name: "avg",
+							Args: []ast.Expr{&ast.ColumnRef{Name: "amount"}},
+							Over: &ast.WindowSpec{
+								Frame: &ast.WindowFrame{
+									Units: "RANGE",
+									Start: ast.FrameBound{Type: "PRECEDING", Offset: &ast.LiteralInt{Value: 3}},
+								},
+							},
+						},
+					},
+				},
+				From: &ast.TableRef{Name: "sales"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with named window",
+			query: "SELECT rank() OVER w, count(*) OVER (w GROUPS BETWEEN 1 PRECEDING AND 1 FOLLOWING) FROM scores WINDOW w AS (PARTITION BY game ORDER BY points) ORDER BY game",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.FuncCall{Name: "rank", Over: &ast.WindowSpec{Name: ast.Identifier{Value: "w"}}}},
+					{
+						Expression: &ast.FuncCall{
+							Name: "count",
+							Star: true,
+							Over: &ast.WindowSpec{
+								Name: ast.Identifier{Value: "w"},
+								Frame: &ast.WindowFrame{
+									Units: "GROUPS",
+									Start: ast.FrameBound{Type: "PRECEDING", Offset: &ast.LiteralInt{Value: 1}},
+									End:   &ast.FrameBound{Type: "FOLLOWING", Offset: &ast.LiteralInt{Value: 1}},
+								},
+							},
+						},
+					},
+				},
+				From: &ast.TableRef{Name: "scores"},
+				Windows: []ast.NamedWindow{
+					{
+						Name: ast.Identifier{Value: "w"},
+						Spec: &ast.WindowSpec{
+							PartitionBy: []ast.Expr{&ast.ColumnRef{Name: "game"}},
+							OrderBy:     []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "points"}}},
+						},
+					},
+				},
+				OrderBy: []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "game"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select distinct",
+			query: "SELECT DISTINCT city FROM users",
+			expected: &ast.SelectStmt{
+				Distinct:    true,
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "city"}}},
+				From:        &ast.TableRef{Name: "users"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select all",
+			query: "SELECT ALL city FROM users",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "city"}}},
+				From:        &ast.TableRef{Name: "users"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select distinct on",
+			query: "SELECT DISTINCT ON (user_id, day) user_id, amount FROM payments ORDER BY user_id, day",
+			expected: &ast.SelectStmt{
+				Distinct:   true,
+				DistinctOn: []ast.Expr{&ast.ColumnRef{Name: "user_id"}, &ast.ColumnRef{Name: "day"}},
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "user_id"}},
+					{Expression: &ast.ColumnRef{Name: "amount"}},
+				},
+				From: &ast.TableRef{Name: "payments"},
+				OrderBy: []ast.OrderByItem{
+					{Expression: &ast.ColumnRef{Name: "user_id"}},
+					{Expression: &ast.ColumnRef{Name: "day"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with qualified names",
+			query: "SELECT u.id, app.public.users.name FROM app.public.users u",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "u"}}, Name: "id"}},
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "app"}, {Value: "public"}, {Value: "users"}}, Name: "name"}},
+				},
+				From: &ast.TableRef{Qualifier: []ast.Identifier{{Value: "app"}, {Value: "public"}}, Name: "users", Alias: ast.Identifier{Value: "u"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with quoted identifiers",
+			query: `SELECT "Order"."user", o."first name", "a""b" FROM "Sales"."Order" o`,
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "Order", Quote: `"`}}, Name: "user", Quote: `"`}},
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "o"}}, Name: "first name", Quote: `"`}},
+					{Expression: &ast.ColumnRef{Name: `a"b`, Quote: `"`}},
+				},
+				From: &ast.TableRef{Qualifier: []ast.Identifier{{Value: "Sales", Quote: `"`}}, Name: "Order", Quote: `"`, Alias: ast.Identifier{Value: "o"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with backtick and bracket identifiers",
+			query: "SELECT `order`.`key`, [dbo].[Users].[Name] FROM `order`",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "order", Quote: "`"}}, Name: "key", Quote: "`"}},
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "dbo", Quote: "["}, {Value: "Users", Quote: "["}}, Name: "Name", Quote: "["}},
+				},
+				From: &ast.TableRef{Name: "order", Quote: "`"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select quoted qualified wildcard",
+			query: `SELECT "U".* FROM users "U"`,
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{IsWildcard: true, Qualifier: []ast.Identifier{{Value: "U", Quote: `"`}}}},
+				From:        &ast.TableRef{Name: "users", Alias: ast.Identifier{Value: "U", Quote: `"`}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with quoted aliases and names",
+			query: `WITH "Recent" AS (SELECT a AS "A" FROM t) SELECT sum("A") OVER "W" FROM "Recent" AS [r] WINDOW "W" AS (ORDER BY "A")`,
+			expected: &ast.SelectStmt{
+				With: &ast.With{
+					CTEs: []ast.CommonTableExpr{
+						{
+							Name: ast.Identifier{Value: "Recent", Quote: `"`},
+							Query: &ast.SelectStmt{
+								Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "a"}, Alias: ast.Identifier{Value: "A", Quote: `"`}}},
+								From:        &ast.TableRef{Name: "t"},
+							},
+						},
+					},
+				},
+				Projections: []ast.ProjectionItem{{Expression: &ast.FuncCall{
+					Name: "sum",
+					Args: []ast.Expr{&ast.ColumnRef{Name: "A", Quote: `"`}},
+					Over: &ast.WindowSpec{Name: ast.Identifier{Value: "W", Quote: `"`}},
+				}}},
+				From: &ast.TableRef{Name: "Recent", Quote: `"`, Alias: ast.Identifier{Value: "r", Quote: "["}},
+				Windows: []ast.NamedWindow{{
+					Name: ast.Identifier{Value: "W", Quote: `"`},
+					Spec: &ast.WindowSpec{OrderBy: []ast.OrderByItem{{Expression: &ast.ColumnRef{Name: "A", Quote: `"`}}}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "select with bind parameters",
+			query: "SELECT name FROM users WHERE id = $1 AND status IN (?, ?) LIMIT :limit OFFSET @offset",
+			expected: &ast.SelectStmt{
+				Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "name"}}},
+				From:        &ast.TableRef{Name: "users"},
+				Selection: &ast.LogicalOp{
+					Left:     &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "id"}, Operator: "=", Right: &ast.Param{Kind: "positional", Marker: "$", Index: 1, Pos: 34}},
+					Operator: "AND",
+					Right: &ast.InList{
+						Expression: &ast.ColumnRef{Name: "status"},
+						List: []ast.Expr{
+							&ast.Param{Kind: "positional", Marker: "?", Index: 1, Pos: 52},
+							&ast.Param{Kind: "positional", Marker: "?", Index: 2, Pos: 55},
+						},
+					},
+				},
+				Limit:  &ast.Param{Kind: "named", Marker: ":", Name: "limit", Pos: 64},
+				Offset: &ast.Param{Kind: "named", Marker: "@", Name: "offset", Pos: 78},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := QueryToAst(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryToAst() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			assertTree(t, result, tt.expected)
+		})
+	}
+}
+
+func TestCreateTableQueries(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected ast.Wrapper
+		wantErr  bool
+	}{
+		{
+			name:  "simple create table",
+			query: "CREATE TABLE users (id INT, name TEXT)",
+			expected: &ast.CreateTableStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Columns: []ast.ColumnDef{
+					{Name: ast.Identifier{Value: "id"}, Type: "INT"},
+					{Name: ast.Identifier{Value: "name"}, Type: "TEXT"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "create table with multiple columns",
+			query: "CREATE TABLE products (id INT, name TEXT, price BIGINT, description TEXT)",
+			expected: &ast.CreateTableStmt{
+				Table: &ast.TableRef{Name: "products"},
+				Columns: []ast.ColumnDef{
+					{Name: ast.Identifier{Value: "id"}, Type: "INT"},
+					{Name: ast.Identifier{Value: "name"}, Type: "TEXT"},
+					{Name: ast.Identifier{Value: "price"}, Type: "BIGINT"},
+					{Name: ast.Identifier{Value: "description"}, Type: "TEXT"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "create table with column constraints",
+			query: "CREATE TABLE users (id BIGINT PRIMARY KEY, email TEXT NOT NULL UNIQUE, nick TEXT NULL, age INT DEFAULT 0 NOT NULL CHECK (age >= 0))",
+			expected: &ast.CreateTableStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Columns: []ast.ColumnDef{
+					{Name: ast.Identifier{Value: "id"}, Type: "BIGINT", Constraints: []ast.ColumnConstraint{{Type: "PRIMARY KEY"}}},
+					{Name: ast.Identifier{Value: "email"}, Type: "TEXT", Constraints: []ast.ColumnConstraint{{Type: "NOT NULL"}, {Type: "UNIQUE"}}},
+					{Name: ast.Identifier{Value: "nick"}, Type: "TEXT", Constraints: []ast.ColumnConstraint{{Type: "NULL"}}},
+					{
+						Name: ast.Identifier{Value: "age"},
+						Type: "INT",
+						Constraints: []ast.ColumnConstraint{
+							{Type: "DEFAULT", Expression: &ast.LiteralInt{Value: 0}},
+							{Type: "NOT NULL"},
+							{
+								Type:       "CHECK",
+								Expression: &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "age"}, Operator: ">=", Right: &ast.LiteralInt{Value: 0}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "create table with named constraints and references",
+			query: "CREATE TABLE orders (id INT CONSTRAINT orders_pk PRIMARY KEY, user_id INT CONSTRAINT orders_user_fk REFERENCES users(id) ON DELETE CASCADE ON UPDATE SET NULL, note TEXT DEFAULT 'n/' || 'a', shop_id INT REFERENCES shops)",
+			expected: &ast.CreateTableStmt{
+				Table: &ast.TableRef{Name: "orders"},
+				Columns: []ast.ColumnDef{
+					{Name: ast.Identifier{Value: "id"}, Type: "INT", Constraints: []ast.ColumnConstraint{{Name: ast.Identifier{Value: "orders_pk"}, Type: "PRIMARY KEY"}}},
+					{
+						Name: ast.Identifier{Value: "user_id"},
+						Type: "INT",
+						Constraints: []ast.ColumnConstraint{
+							{
+								Name: ast.Identifier{Value: "orders_user_fk"},
+								Type: "REFERENCES",
+								References: &ast.ForeignKey{
+									Table:    &ast.TableRef{Name: "users"},
+									Columns:  []ast.Identifier{{Value: "id"}},
+									OnDelete: "CASCADE",
+									OnUpdate: "SET NULL",
+								},
+							},
+						},
+					},
+					{
+						Name: ast.Identifier{Value: "note"},
+						Type: "TEXT",
+						Constraints: []ast.ColumnConstraint{
+							{
+								Type:       "DEFAULT",
+								Expression: &ast.BinaryOp{Left: &ast.LiteralString{Value: "n/"}, Operator: "||", Right: &ast.LiteralString{Value: "a"}},
+							},
+						},
+					},
+					{
+						Name:        ast.Identifier{Value: "shop_id"},
+						Type:        "INT",
+						Constraints: []ast.ColumnConstraint{{Type: "REFERENCES", References: &ast.ForeignKey{Table: &ast.TableRef{Name: "shops"}}}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "create table with delimited names",
+			query: `CREATE TABLE app."Order" (shop_id INT REFERENCES app."Shops" ("Id"))`,
+			expected: &ast.CreateTableStmt{
+				Table: &ast.TableRef{Qualifier: []ast.Identifier{{Value: "app"}}, Name: "Order", Quote: `"`},
+				Columns: []ast.ColumnDef{
+					{
+						Name: ast.Identifier{Value: "shop_id"},
+						Type: "INT",
+						Constraints: []ast.ColumnConstraint{
+							{
+								Type: "REFERENCES",
+								References: &ast.ForeignKey{
+									Table:   &ast.TableRef{Qualifier: []ast.Identifier{{Value: "app"}}, Name: "Shops", Quote: `"`},
+									Columns: []ast.Identifier{{Value: "Id", Quote: `"`}},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := QueryToAst(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryToAst() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			assertTree(t, result, tt.expected)
+		})
+	}
+}
+
+func TestInsertQueries(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected ast.Wrapper
+		wantErr  bool
+	}{
+		{
+			name:  "simple insert",
+			query: "INSERT INTO users VALUES (1, 'Alice')",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Rows: [][]ast.Expr{
+					{
+						&ast.LiteralInt{Value: 1},
+						&ast.LiteralString{Value: "Alice"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with multiple integer values",
+			query: "INSERT INTO numbers VALUES (1, 2, 3)",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "numbers"},
+				Rows: [][]ast.Expr{
+					{
+						&ast.LiteralInt{Value: 1},
+						&ast.LiteralInt{Value: 2},
+						&ast.LiteralInt{Value: 3},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with mixed values",
+			query: "INSERT INTO products VALUES (1, 'Laptop', 1200, 'High performance laptop')",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "products"},
+				Rows: [][]ast.Expr{
+					{
+						&ast.LiteralInt{Value: 1},
+						&ast.LiteralString{Value: "Laptop"},
+						&ast.LiteralInt{Value: 1200},
+						&ast.LiteralString{Value: "High performance laptop"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with float, boolean, negative and escaped values",
+			query: "INSERT INTO accounts VALUES (-7, 2.50, 6.02e23, FALSE, NULL, 'it''s')",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "accounts"},
+				Rows: [][]ast.Expr{
+					{
+						&ast.LiteralInt{Value: -7},
+						&ast.LiteralDecimal{Value: "2.50"},
+						&ast.LiteralFloat{Value: 6.02e23},
+						&ast.LiteralBool{Value: false},
+						&ast.LiteralNull{},
+						&ast.LiteralString{Value: "it's"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with function call values",
+			query: "INSERT INTO events VALUES (now(), upper('login'))",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "events"},
+				Rows: [][]ast.Expr{
+					{
+						&ast.FuncCall{Name: "now"},
+						&ast.FuncCall{Name: "upper", Args: []ast.Expr{&ast.LiteralString{Value: "login"}}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with bind parameters",
+			query: "INSERT INTO users VALUES ($1, $2, :name)",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Rows: [][]ast.Expr{
+					{
+						&ast.Param{Kind: "positional", Marker: "$", Index: 1, Pos: 26},
+						&ast.Param{Kind: "positional", Marker: "$", Index: 2, Pos: 30},
+						&ast.Param{Kind: "named", Marker: ":", Name: "name", Pos: 34},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with column list and several rows",
+			query: "INSERT INTO users (id, name) VALUES (1, 'x'), (2, 'y')",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "users"},
+				Columns: []ast.Identifier{{Value: "id"}, {Value: "name"}},
+				Rows: [][]ast.Expr{
+					{&ast.LiteralInt{Value: 1}, &ast.LiteralString{Value: "x"}},
+					{&ast.LiteralInt{Value: 2}, &ast.LiteralString{Value: "y"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with delimited names",
+			query: "INSERT INTO shop.\"Order\" (\"Id\", `total`) VALUES (1, 2) ON CONFLICT (\"Id\") DO NOTHING",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Qualifier: []ast.Identifier{{Value: "shop"}}, Name: "Order", Quote: `"`},
+				Columns: []ast.Identifier{{Value: "Id", Quote: `"`}, {Value: "total", Quote: "`"}},
+				Rows: [][]ast.Expr{
+					{&ast.LiteralInt{Value: 1}, &ast.LiteralInt{Value: 2}},
+				},
+				OnConflict: &ast.OnConflict{Columns: []ast.Identifier{{Value: "Id", Quote: `"`}}, Action: "NOTHING"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with DEFAULT value",
+			query: "INSERT INTO users(id, created_at) VALUES (7, DEFAULT)",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "users"},
+				Columns: []ast.Identifier{{Value: "id"}, {Value: "created_at"}},
+				Rows: [][]ast.Expr{
+					{&ast.LiteralInt{Value: 7}, &ast.Default{}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert DEFAULT VALUES",
+			query: "INSERT INTO audit DEFAULT VALUES",
+			expected: &ast.InsertStmt{
+				Table:         &ast.TableRef{Name: "audit"},
+				DefaultValues: true,
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert from select",
+			query: "INSERT INTO archive (id, name) SELECT id, name FROM users WHERE deleted",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "archive"},
+				Columns: []ast.Identifier{{Value: "id"}, {Value: "name"}},
+				Query: &ast.SelectStmt{
+					Projections: []ast.ProjectionItem{
+						{Expression: &ast.ColumnRef{Name: "id"}},
+						{Expression: &ast.ColumnRef{Name: "name"}},
+					},
+					From:      &ast.TableRef{Name: "users"},
+					Selection: &ast.ColumnRef{Name: "deleted"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert from parenthesized select",
+			query: "INSERT INTO archive (SELECT id FROM users) RETURNING id",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "archive"},
+				Query: &ast.SelectStmt{
+					Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "id"}}},
+					From:        &ast.TableRef{Name: "users"},
+				},
+				Returning: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "id"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert from set operation with parenthesized operands",
+			query: "INSERT INTO archive (id) ((SELECT id FROM users) UNION (SELECT id FROM admins))",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "archive"},
+				Columns: []ast.Identifier{{Value: "id"}},
+				Query: &ast.SetOperation{
+					Operator: "UNION",
+					Left: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "id"}}},
+						From:        &ast.TableRef{Name: "users"},
+					},
+					Right: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "id"}}},
+						From:        &ast.TableRef{Name: "admins"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert with returning",
+			query: "INSERT INTO users (name) VALUES ('x') RETURNING id, created_at AS created",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "users"},
+				Columns: []ast.Identifier{{Value: "name"}},
+				Rows:    [][]ast.Expr{{&ast.LiteralString{Value: "x"}}},
+				Returning: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Name: "id"}},
+					{Expression: &ast.ColumnRef{Name: "created_at"}, Alias: ast.Identifier{Value: "created"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert from select with returning all columns",
+			query: "INSERT INTO totals WITH t AS (SELECT 1 FROM x) SELECT * FROM t RETURNING *",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "totals"},
+				Query: &ast.SelectStmt{
+					With: &ast.With{
+						CTEs: []ast.CommonTableExpr{
+							{
+								Name: ast.Identifier{Value: "t"},
+								Query: &ast.SelectStmt{
+									Projections: []ast.ProjectionItem{{Expression: &ast.LiteralInt{Value: 1}}},
+									From:        &ast.TableRef{Name: "x"},
+								},
+							},
+						},
+					},
+					Projections: []ast.ProjectionItem{{IsWildcard: true}},
+					From:        &ast.TableRef{Name: "t"},
+				},
+				Returning: []ast.ProjectionItem{{IsWildcard: true}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert on conflict do nothing",
+			query: "INSERT INTO users (id) VALUES (1) ON CONFLICT DO NOTHING",
+			expected: &ast.InsertStmt{
+				Table:      &ast.TableRef{Name: "users"},
+				Columns:    []ast.Identifier{{Value: "id"}},
+				Rows:       [][]ast.Expr{{&ast.LiteralInt{Value: 1}}},
+				OnConflict: &ast.OnConflict{Action: "NOTHING"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert on conflict do update",
+			query: "INSERT INTO counters (slug, hits) VALUES ('a', 1) ON CONFLICT (slug) DO UPDATE SET hits = counters.hits + EXCLUDED.hits WHERE counters.locked = false RETURNING hits",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "counters"},
+				Columns: []ast.Identifier{{Value: "slug"}, {Value: "hits"}},
+				Rows:    [][]ast.Expr{{&ast.LiteralString{Value: "a"}, &ast.LiteralInt{Value: 1}}},
+				OnConflict: &ast.OnConflict{
+					Columns: []ast.Identifier{{Value: "slug"}},
+					Action:  "UPDATE",
+					Set: []ast.Assignment{
+						{
+							Columns: []ast.Identifier{{Value: "hits"}},
+							Values: []ast.Expr{
+								&ast.BinaryOp{
+									Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "counters"}}, Name: "hits"},
+									Operator: "+",
+									Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "EXCLUDED"}}, Name: "hits"},
+								},
+							},
+						},
+					},
+					Where: &ast.ComparisonOp{
+						Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "counters"}}, Name: "locked"},
+						Operator: "=",
+						Right:    &ast.LiteralBool{Value: false},
+					},
+				},
+				Returning: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "hits"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert on conflict on constraint with multiple column set",
+			query: "INSERT INTO users VALUES (1, 'a', 'b') ON CONFLICT ON CONSTRAINT users_pkey DO UPDATE SET (first, last) = (EXCLUDED.first, DEFAULT)",
+			expected: &ast.InsertStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Rows: [][]ast.Expr{
+					{&ast.LiteralInt{Value: 1}, &ast.LiteralString{Value: "a"}, &ast.LiteralString{Value: "b"}},
+				},
+				OnConflict: &ast.OnConflict{
+					Constraint: ast.Identifier{Value: "users_pkey"},
+					Action:     "UPDATE",
+					Set: []ast.Assignment{
+						{
+							Columns: []ast.Identifier{{Value: "first"}, {Value: "last"}},
+							Values: []ast.Expr{
+								&ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "EXCLUDED"}}, Name: "first"},
+								&ast.Default{},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert on duplicate key update",
+			query: "INSERT INTO stats (day, views) VALUES ('mon', 1) ON DUPLICATE KEY UPDATE views = views + 1, day = 'mon'",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "stats"},
+				Columns: []ast.Identifier{{Value: "day"}, {Value: "views"}},
+				Rows:    [][]ast.Expr{{&ast.LiteralString{Value: "mon"}, &ast.LiteralInt{Value: 1}}},
+				OnConflict: &ast.OnConflict{
+					DuplicateKey: true,
+					Action:       "UPDATE",
+					Set: []ast.Assignment{
+						{
+							Columns: []ast.Identifier{{Value: "views"}},
+							Values:  []ast.Expr{&ast.BinaryOp{Left: &ast.ColumnRef{Name: "views"}, Operator: "+", Right: &ast.LiteralInt{Value: 1}}},
+						},
+						{
+							Columns: []ast.Identifier{{Value: "day"}},
+							Values:  []ast.Expr{&ast.LiteralString{Value: "mon"}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "insert on duplicate key update with values function",
+			query: "INSERT INTO stats (day, views) VALUES ('mon', 1) ON DUPLICATE KEY UPDATE views = views + VALUES(views)",
+			expected: &ast.InsertStmt{
+				Table:   &ast.TableRef{Name: "stats"},
+				Columns: []ast.Identifier{{Value: "day"}, {Value: "views"}},
+				Rows:    [][]ast.Expr{{&ast.LiteralString{Value: "mon"}, &ast.LiteralInt{Value: 1}}},
+				OnConflict: &ast.OnConflict{
+					DuplicateKey: true,
+					Action:       "UPDATE",
+					Set: []ast.Assignment{
+						{
+							Columns: []ast.Identifier{{Value: "views"}},
+							Values: []ast.Expr{
+								&ast.BinaryOp{
+									Left:     &ast.ColumnRef{Name: "views"},
+									Operator: "+",
+									Right:    &ast.FuncCall{Name: "VALUES", Args: []ast.Expr{&ast.ColumnRef{Name: "views"}}},
+								},
+							},
+						},
+					},
+				},
 			},
 			wantErr: false,
 		},
@@ -84,25 +1770,12 @@ func TestSelectQueries(t *testing.T) {
 				return
 			}
 
-			// Check the type first
-			if reflect.TypeOf(result) != reflect.TypeOf(tt.expected) {
-				t.Errorf("QueryToAst() result type = %T, expected type %T", result, tt.expected)
-				return
-			}
-
-			// Convert to JSON strings for comparison
-			resultJSON := result.String()
-			expectedJSON := tt.expected.String()
-
-			// Compare JSON strings
-			if resultJSON != expectedJSON {
-				t.Errorf("QueryToAst() result = %s\nexpected = %s", resultJSON, expectedJSON)
-			}
+			assertTree(t, result, tt.expected)
 		})
 	}
 }
 
-func TestCreateTableQueries(t *testing.T) {
+func TestUpdateQueries(t *testing.T) {
 	tests := []struct {
 		name     string
 		query    string
@@ -110,27 +1783,99 @@ func TestCreateTableQueries(t *testing.T) {
 		wantErr  bool
 	}{
 		{
-			name:  "simple create table",
-			query: "CREATE TABLE users (id INT, name TEXT)",
-			expected: &ast.CreateTableStmt{
-				TableName: "users",
-				Columns: []ast.ColumnDef{
-					{Name: "id", Type: "INT"},
-					{Name: "name", Type: "TEXT"},
+			name:  "simple update",
+			query: "UPDATE users SET name = 'Bob' WHERE id = 1",
+			expected: &ast.UpdateStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Set: []ast.Assignment{
+					{Columns: []ast.Identifier{{Value: "name"}}, Values: []ast.Expr{&ast.LiteralString{Value: "Bob"}}},
 				},
+				Selection: &ast.ComparisonOp{Left: &ast.ColumnRef{Name: "id"}, Operator: "=", Right: &ast.LiteralInt{Value: 1}},
 			},
 			wantErr: false,
 		},
 		{
-			name:  "create table with multiple columns",
-			query: "CREATE TABLE products (id INT, name TEXT, price BIGINT, description TEXT)",
-			expected: &ast.CreateTableStmt{
-				TableName: "products",
-				Columns: []ast.ColumnDef{
-					{Name: "id", Type: "INT"},
-					{Name: "name", Type: "TEXT"},
-					{Name: "price", Type: "BIGINT"},
-					{Name: "description", Type: "TEXT"},
+			name:  "update with delimited names",
+			query: `UPDATE app."T" SET "user" = 1, ("a b", c) = (2, 3)`,
+			expected: &ast.UpdateStmt{
+				Table: &ast.TableRef{Qualifier: []ast.Identifier{{Value: "app"}}, Name: "T", Quote: `"`},
+				Set: []ast.Assignment{
+					{Columns: []ast.Identifier{{Value: "user", Quote: `"`}}, Values: []ast.Expr{&ast.LiteralInt{Value: 1}}},
+					{Columns: []ast.Identifier{{Value: "a b", Quote: `"`}, {Value: "c"}}, Values: []ast.Expr{&ast.LiteralInt{Value: 2}, &ast.LiteralInt{Value: 3}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update with alias and several assignments",
+			query: "UPDATE accounts AS a SET balance = a.balance - 10, (status, note) = ('closed', DEFAULT), updated_at = now()",
+			expected: &ast.UpdateStmt{
+				Table: &ast.TableRef{Name: "accounts", Alias: ast.Identifier{Value: "a"}},
+				Set: []ast.Assignment{
+					{
+						Columns: []ast.Identifier{{Value: "balance"}},
+						Values: []ast.Expr{
+							&ast.BinaryOp{Left: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "a"}}, Name: "balance"}, Operator: "-", Right: &ast.LiteralInt{Value: 10}},
+						},
+					},
+					{
+						Columns: []ast.Identifier{{Value: "status"}, {Value: "note"}},
+						Values:  []ast.Expr{&ast.LiteralString{Value: "closed"}, &ast.Default{}},
+					},
+					{
+						Columns: []ast.Identifier{{Value: "updated_at"}},
+						Values:  []ast.Expr{&ast.FuncCall{Name: "now"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update from another table with returning",
+			query: "UPDATE orders o SET total = t.sum FROM totals t WHERE o.id = t.order_id RETURNING o.id, total",
+			expected: &ast.UpdateStmt{
+				Table: &ast.TableRef{Name: "orders", Alias: ast.Identifier{Value: "o"}},
+				Set: []ast.Assignment{
+					{Columns: []ast.Identifier{{Value: "total"}}, Values: []ast.Expr{&ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "t"}}, Name: "sum"}}},
+				},
+				From: &ast.TableRef{Name: "totals", Alias: ast.Identifier{Value: "t"}},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "o"}}, Name: "id"},
+					Operator: "=",
+					Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "t"}}, Name: "order_id"},
+				},
+				Returning: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "o"}}, Name: "id"}},
+					{Expression: &ast.ColumnRef{Name: "total"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "update with subquery assignment",
+			query: "UPDATE users SET (city, zip) = (SELECT city, zip FROM addresses WHERE user_id = users.id)",
+			expected: &ast.UpdateStmt{
+				Table: &ast.TableRef{Name: "users"},
+				Set: []ast.Assignment{
+					{
+						Columns: []ast.Identifier{{Value: "city"}, {Value: "zip"}},
+						Values: []ast.Expr{
+							&ast.Subquery{
+								Query: &ast.SelectStmt{
+									Projections: []ast.ProjectionItem{
+										{Expression: &ast.ColumnRef{Name: "city"}},
+										{Expression: &ast.ColumnRef{Name: "zip"}},
+									},
+									From: &ast.TableRef{Name: "addresses"},
+									Selection: &ast.ComparisonOp{
+										Left:     &ast.ColumnRef{Name: "user_id"},
+										Operator: "=",
+										Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "users"}}, Name: "id"},
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 			wantErr: false,
@@ -149,25 +1894,88 @@ func TestCreateTableQueries(t *testing.T) {
 				return
 			}
 
-			// Check the type first
-			if reflect.TypeOf(result) != reflect.TypeOf(tt.expected) {
-				t.Errorf("QueryToAst() result type = %T, expected type %T", result, tt.expected)
+			assertTree(t, result, tt.expected)
+		})
+	}
+}
+
+func TestDeleteQueries(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected ast.Wrapper
+		wantErr  bool
+	}{
+		{
+			name:  "delete all rows",
+			query: "DELETE FROM sessions",
+			expected: &ast.DeleteStmt{
+				Table: &ast.TableRef{Name: "sessions"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "delete with where and returning",
+			query: "DELETE FROM sessions s WHERE s.expires_at < now() RETURNING s.id",
+			expected: &ast.DeleteStmt{
+				Table: &ast.TableRef{Name: "sessions", Alias: ast.Identifier{Value: "s"}},
+				Selection: &ast.ComparisonOp{
+					Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "expires_at"},
+					Operator: "<",
+					Right:    &ast.FuncCall{Name: "now"},
+				},
+				Returning: []ast.ProjectionItem{
+					{Expression: &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "id"}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "delete with using",
+			query: "DELETE FROM orders USING users u, banned b WHERE orders.user_id = u.id AND u.id = b.user_id",
+			expected: &ast.DeleteStmt{
+				Table: &ast.TableRef{Name: "orders"},
+				Using: &ast.Join{
+					Type:  "CROSS",
+					Left:  &ast.TableRef{Name: "users", Alias: ast.Identifier{Value: "u"}},
+					Right: &ast.TableRef{Name: "banned", Alias: ast.Identifier{Value: "b"}},
+				},
+				Selection: &ast.LogicalOp{
+					Left: &ast.ComparisonOp{
+						Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "orders"}}, Name: "user_id"},
+						Operator: "=",
+						Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "u"}}, Name: "id"},
+					},
+					Operator: "AND",
+					Right: &ast.ComparisonOp{
+						Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "u"}}, Name: "id"},
+						Operator: "=",
+						Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "b"}}, Name: "user_id"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := QueryToAst(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryToAst() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
-			// Convert to JSON strings for comparison
-			resultJSON := result.String()
-			expectedJSON := tt.expected.String()
-
-			// Compare JSON strings
-			if resultJSON != expectedJSON {
-				t.Errorf("QueryToAst() result = %s\nexpected = %s", resultJSON, expectedJSON)
+			if err != nil {
+				return
 			}
+
+			assertTree(t, result, tt.expected)
 		})
 	}
 }
 
-func TestInsertQueries(t *testing.T) {
+func TestTruncateQueries(t *testing.T) {
 	tests := []struct {
 		name     string
 		query    string
@@ -175,40 +1983,136 @@ func TestInsertQueries(t *testing.T) {
 		wantErr  bool
 	}{
 		{
-			name:  "simple insert",
-			query: "INSERT INTO users VALUES (1, 'Alice')",
-			expected: &ast.InsertStmt{
-				TableName: "users",
-				Values: []ast.Expr{
-					&ast.LiteralInt{Value: 1},
-					&ast.LiteralString{Value: "Alice"},
+			name:  "truncate one table",
+			query: "TRUNCATE logs",
+			expected: &ast.TruncateStmt{
+				Tables: []*ast.TableRef{{Name: "logs"}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "truncate several tables with options",
+			query: "TRUNCATE TABLE public.logs, events RESTART IDENTITY CASCADE",
+			expected: &ast.TruncateStmt{
+				Tables: []*ast.TableRef{
+					{Qualifier: []ast.Identifier{{Value: "public"}}, Name: "logs"},
+					{Name: "events"},
 				},
+				RestartIdentity: true,
+				Cascade:         true,
 			},
 			wantErr: false,
 		},
 		{
-			name:  "insert with multiple integer values",
-			query: "INSERT INTO numbers VALUES (1, 2, 3)",
-			expected: &ast.InsertStmt{
-				TableName: "numbers",
-				Values: []ast.Expr{
-					&ast.LiteralInt{Value: 1},
-					&ast.LiteralInt{Value: 2},
-					&ast.LiteralInt{Value: 3},
+			name:  "truncate with default options spelled out",
+			query: "TRUNCATE events CONTINUE IDENTITY RESTRICT",
+			expected: &ast.TruncateStmt{
+				Tables: []*ast.TableRef{{Name: "events"}},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := QueryToAst(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("QueryToAst() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			assertTree(t, result, tt.expected)
+		})
+	}
+}
+
+func TestMergeQueries(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected ast.Wrapper
+		wantErr  bool
+	}{
+		{
+			name: "merge with ordered when clauses",
+			query: "MERGE INTO stock s USING deliveries d ON s.item = d.item " +
+				"WHEN MATCHED AND s.qty + d.qty = 0 THEN DELETE " +
+				"WHEN MATCHED THEN UPDATE SET qty = s.qty + d.qty " +
+				"WHEN NOT MATCHED THEN INSERT (item, qty) VALUES (d.item, d.qty)",
+			expected: &ast.MergeStmt{
+				Target: &ast.TableRef{Name: "stock", Alias: ast.Identifier{Value: "s"}},
+				Source: &ast.TableRef{Name: "deliveries", Alias: ast.Identifier{Value: "d"}},
+				On: &ast.ComparisonOp{
+					Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "item"},
+					Operator: "=",
+					Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "d"}}, Name: "item"},
+				},
+				Whens: []ast.MergeWhen{
+					{
+						Matched: true,
+						Condition: &ast.ComparisonOp{
+							Left: &ast.BinaryOp{
+								Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "qty"},
+								Operator: "+",
+								Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "d"}}, Name: "qty"},
+							},
+							Operator: "=",
+							Right:    &ast.LiteralInt{Value: 0},
+						},
+						Action: "DELETE",
+					},
+					{
+						Matched: true,
+						Action:  "UPDATE",
+						Set: []ast.Assignment{
+							{
+								Columns: []ast.Identifier{{Value: "qty"}},
+								Values: []ast.Expr{
+									&ast.BinaryOp{
+										Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "qty"},
+										Operator: "+",
+										Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "d"}}, Name: "qty"},
+									},
+								},
+							},
+						},
+					},
+					{
+						Action:  "INSERT",
+						Columns: []ast.Identifier{{Value: "item"}, {Value: "qty"}},
+						Values: []ast.Expr{
+							&ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "d"}}, Name: "item"},
+							&ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "d"}}, Name: "qty"},
+						},
+					},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name:  "insert with mixed values",
-			query: "INSERT INTO products VALUES (1, 'Laptop', 1200, 'High performance laptop')",
-			expected: &ast.InsertStmt{
-				TableName: "products",
-				Values: []ast.Expr{
-					&ast.LiteralInt{Value: 1},
-					&ast.LiteralString{Value: "Laptop"},
-					&ast.LiteralInt{Value: 1200},
-					&ast.LiteralString{Value: "High performance laptop"},
+			name:  "merge from subquery with do nothing and default values",
+			query: "MERGE INTO t USING (SELECT id FROM src) AS s ON t.id = s.id WHEN MATCHED THEN DO NOTHING WHEN NOT MATCHED THEN INSERT DEFAULT VALUES",
+			expected: &ast.MergeStmt{
+				Target: &ast.TableRef{Name: "t"},
+				Source: &ast.DerivedTable{
+					Query: &ast.SelectStmt{
+						Projections: []ast.ProjectionItem{{Expression: &ast.ColumnRef{Name: "id"}}},
+						From:        &ast.TableRef{Name: "src"},
+					},
+					Alias: ast.Identifier{Value: "s"},
+				},
+				On: &ast.ComparisonOp{
+					Left:     &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "t"}}, Name: "id"},
+					Operator: "=",
+					Right:    &ast.ColumnRef{Qualifier: []ast.Identifier{{Value: "s"}}, Name: "id"},
+				},
+				Whens: []ast.MergeWhen{
+					{Matched: true, Action: "NOTHING"},
+					{Action: "INSERT", DefaultValues: true},
 				},
 			},
 			wantErr: false,
@@ -227,20 +2131,242 @@ func TestInsertQueries(t *testing.T) {
 				return
 			}
 
-			// Check the type first
-			if reflect.TypeOf(result) != reflect.TypeOf(tt.expected) {
-				t.Errorf("QueryToAst() result type = %T, expected type %T", result, tt.expected)
+			assertTree(t, result, tt.expected)
+		})
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	tests := []struct {
+		name     string
+		script   string
+		expected []string // source text of each statement
+		wantErr  bool
+	}{
+		{
+			name:     "statements separated by semicolons",
+			script:   "CREATE TABLE t (id INT); INSERT INTO t VALUES (1);\nSELECT id FROM t",
+			expected: []string{"CREATE TABLE t (id INT)", "INSERT INTO t VALUES (1)", "SELECT id FROM t"},
+			wantErr:  false,
+		},
+		{
+			name:     "empty statements",
+			script:   ";; SELECT a FROM t;;\n;",
+			expected: []string{"SELECT a FROM t"},
+			wantErr:  false,
+		},
+		{
+			name:     "semicolons in strings and comments",
+			script:   "-- setup; not a statement\nINSERT INTO t VALUES ('a;b'); /* x; y */ SELECT a FROM t",
+			expected: []string{"INSERT INTO t VALUES ('a;b')", "SELECT a FROM t"},
+			wantErr:  false,
+		},
+		{
+			name:     "empty script",
+			script:   "  -- nothing here\n",
+			expected: nil,
+			wantErr:  false,
+		},
+		{
+			name:    "error in a later statement",
+			script:  "SELECT a FROM t; SELECT FROM t",
+			wantErr: true,
+		},
+		{
+			name:    "missing semicolon between statements",
+			script:  "SELECT a FROM t SELECT b FROM u",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseScript(tt.script)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseScript() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
 				return
 			}
 
-			// Convert to JSON strings for comparison
-			resultJSON := result.String()
-			expectedJSON := tt.expected.String()
+			var statements []string
+			for _, stmt := range result {
+				span := stmt.SourceSpan()
+				statements = append(statements, tt.script[span.Start:span.End])
+			}
+
+			if !reflect.DeepEqual(statements, tt.expected) {
+				t.Errorf("ParseScript() statements = %q, expected %q", statements, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []ast.Param // compared without Pos
+	}{
+		{
+			name:     "no parameters",
+			query:    "SELECT a FROM t WHERE a = 1",
+			expected: nil,
+		},
+		{
+			name:  "parameters in source order",
+			query: "SELECT a + $2 FROM t WHERE b = :name ORDER BY a OFFSET $1 LIMIT @n",
+			expected: []ast.Param{
+				{Kind: "positional", Marker: "$", Index: 2},
+				{Kind: "named", Marker: ":", Name: "name"},
+				{Kind: "positional", Marker: "$", Index: 1},
+				{Kind: "named", Marker: "@", Name: "n"},
+			},
+		},
+		{
+			name:  "mixed parameters in subqueries and joins",
+			query: "WITH x AS (SELECT id FROM t WHERE k = :k) SELECT id FROM x JOIN u ON u.id = x.id AND u.v = $2 WHERE id IN (SELECT id FROM w WHERE z = @z) OFFSET $1",
+			expected: []ast.Param{
+				{Kind: "named", Marker: ":", Name: "k"},
+				{Kind: "positional", Marker: "$", Index: 2},
+				{Kind: "named", Marker: "@", Name: "z"},
+				{Kind: "positional", Marker: "$", Index: 1},
+			},
+		},
+		{
+			name:  "question marks numbered in order",
+			query: "INSERT INTO t VALUES (?, lower(?)) RETURNING ?",
+			expected: []ast.Param{
+				{Kind: "positional", Marker: "?", Index: 1},
+				{Kind: "positional", Marker: "?", Index: 2},
+				{Kind: "positional", Marker: "?", Index: 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			var params []ast.Param
+			for _, param := range ast.Params(stmt) {
+				p := *param
+				p.Pos = 0
+				params = append(params, p)
+			}
+
+			if !reflect.DeepEqual(params, tt.expected) {
+				t.Errorf("Params() = %+v, expected %+v", params, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSQLString(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "nested negation",
+			query:    "SELECT - -1, -(-a), +-b FROM t",
+			expected: "SELECT -(-1), -(-a), +(-b) FROM t",
+		},
+		{
+			name:     "parenthesized query with clauses at both levels",
+			query:    "(SELECT a FROM t LIMIT 10) ORDER BY a",
+			expected: "(SELECT a FROM t LIMIT 10) ORDER BY a",
+		},
+		{
+			name:     "common table expressions at two levels",
+			query:    "WITH y AS (SELECT 2 FROM u) (WITH x AS (SELECT 1 FROM t) SELECT * FROM x)",
+			expected: "WITH y AS (SELECT 2 FROM u) (WITH x AS (SELECT 1 FROM t) SELECT * FROM x)",
+		},
+		{
+			name:     "set operand with its own common table expression",
+			query:    "(WITH x AS (SELECT a FROM t) SELECT a FROM x) UNION SELECT b FROM s",
+			expected: "(WITH x AS (SELECT a FROM t) SELECT a FROM x) UNION SELECT b FROM s",
+		},
+		{
+			name:     "right set operand with its own common table expression",
+			query:    "SELECT b FROM s UNION (WITH x AS (SELECT a FROM t) SELECT a FROM x)",
+			expected: "SELECT b FROM s UNION (WITH x AS (SELECT a FROM t) SELECT a FROM x)",
+		},
+		{
+			name:     "logical operation compared to a value",
+			query:    "SELECT a FROM t WHERE (a OR b) = TRUE",
+			expected: "SELECT a FROM t WHERE (a OR b) = true",
+		},
+		{
+			name:     "comparisons compared to each other",
+			query:    "SELECT a FROM t WHERE (a = 1) = (b = 2)",
+			expected: "SELECT a FROM t WHERE (a = 1) = (b = 2)",
+		},
+		{
+			name:     "predicates as operands",
+			query:    "SELECT a FROM t WHERE (a IS NULL) = (b IN (1, 2)) AND (c LIKE 'x%') IS NOT NULL",
+			expected: "SELECT a FROM t WHERE (a IS NULL) = (b IN (1, 2)) AND (c LIKE 'x%') IS NOT NULL",
+		},
+		{
+			name:     "aliased parenthesized joins",
+			query:    "SELECT * FROM a JOIN (b JOIN c ON b.id = c.id) AS x ON a.id = x.id",
+			expected: "SELECT * FROM a INNER JOIN (b INNER JOIN c ON b.id = c.id) x ON a.id = x.id",
+		},
+		{
+			name:     "negative operand of subtraction",
+			query:    "SELECT a - -1 FROM t",
+			expected: "SELECT (a - -1) FROM t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+
+			query, ok := stmt.(ast.QueryExpr)
+			if !ok {
+				t.Fatalf("ParseQuery() result type = %T, expected a query", stmt)
+			}
+
+			result := query.SQLString()
+			if result != tt.expected {
+				t.Errorf("SQLString() = %q, expected %q", result, tt.expected)
+			}
 
-			// Compare JSON strings
-			if resultJSON != expectedJSON {
-				t.Errorf("QueryToAst() result = %s\nexpected = %s", resultJSON, expectedJSON)
+			// the printed SQL must parse back to the same tree
+			reparsed, err := ParseQuery(result)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", result, err)
 			}
+			stmt.SetSourceSpan(ast.Span{})
+			assertTree(t, reparsed, stmt)
 		})
 	}
 }
+
+// assertTree checks a parsed statement against the expected tree. The trees
+// are compared directly rather than through their JSON, in which nodes of
+// different types with the same fields look alike. Source spans are left to
+// TestParseScript.
+func assertTree(t *testing.T, result, expected ast.Wrapper) {
+	t.Helper()
+
+	if reflect.TypeOf(result) != reflect.TypeOf(expected) {
+		t.Errorf("QueryToAst() result type = %T, expected type %T", result, expected)
+		return
+	}
+
+	result.(ast.Statement).SetSourceSpan(ast.Span{})
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("QueryToAst() result = %s\nexpected = %s", result.String(), expected.String())
+	}
+}
@@ -0,0 +1,406 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cockatoo/ast"
+)
+
+// resultClauses holds the ORDER BY, LIMIT, OFFSET and FETCH clauses that
+// shape the rows of a whole query, whether a single SELECT or a set
+// operation.
+type resultClauses struct {
+	OrderBy  []ast.OrderByItem
+	Limit    ast.Expr
+	LimitAll bool
+	Offset   ast.Expr
+	Fetch    *ast.Fetch
+}
+
+// parseQuery parses a SELECT or a chain of set operations over SELECTs,
+// followed by the result clauses that apply to the combined rows. It stops
+// at the first token that cannot continue the query, leaving the caller to
+// check for end of input or a closing parenthesis.
+func parseQuery(ts *TokenStream) (ast.QueryExpr, error) {
+	var with *ast.With
+	if _, val := ts.Current(); strings.ToUpper(val) == T_WITH {
+		var err error
+		with, err = parseWithClause(ts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := parseSetExpression(ts, precedenceLowest)
+	if err != nil {
+		return nil, err
+	}
+
+	clauses, err := parseResultClauses(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	query = applyResultClauses(query, clauses)
+	if with != nil {
+		query = applyWith(query, with)
+	}
+
+	return query, nil
+}
+
+// applyWith attaches the WITH list written before a query. A parenthesized
+// query with a WITH list of its own is wrapped in a ParenQuery, so that
+// both lists are kept and the inner one stays scoped to the parentheses.
+func applyWith(query ast.QueryExpr, with *ast.With) ast.QueryExpr {
+	switch q := query.(type) {
+	case *ast.SelectStmt:
+		if q.With == nil {
+			q.With = with
+			return q
+		}
+	case *ast.SetOperation:
+		if q.With == nil {
+			q.With = with
+			return q
+		}
+	case *ast.ParenQuery:
+		if q.With == nil {
+			q.With = with
+			return q
+		}
+	}
+	return &ast.ParenQuery{With: with, Query: query}
+}
+
+// applyResultClauses attaches the result clauses written after a query. A
+// parenthesized query may already carry clauses of its own; the new ones
+// are merged into it when they only apply after those, as in
+// (SELECT ... ORDER BY a) LIMIT 1, and otherwise the query is wrapped in
+// a ParenQuery so that both levels are kept.
+func applyResultClauses(query ast.QueryExpr, clauses *resultClauses) ast.QueryExpr {
+	own := queryResultClauses(query)
+	if _, last := own.stages(); last >= 0 {
+		if first, _ := clauses.stages(); first >= 0 && first <= last {
+			query = &ast.ParenQuery{Query: query}
+			own = &resultClauses{}
+		}
+	}
+
+	if clauses.OrderBy != nil {
+		own.OrderBy = clauses.OrderBy
+	}
+	if clauses.Limit != nil || clauses.LimitAll {
+		own.Limit = clauses.Limit
+		own.LimitAll = clauses.LimitAll
+	}
+	if clauses.Offset != nil {
+		own.Offset = clauses.Offset
+	}
+	if clauses.Fetch != nil {
+		own.Fetch = clauses.Fetch
+	}
+
+	switch q := query.(type) {
+	case *ast.SelectStmt:
+		q.OrderBy = own.OrderBy
+		q.Limit = own.Limit
+		q.LimitAll = own.LimitAll
+		q.Offset = own.Offset
+		q.Fetch = own.Fetch
+	case *ast.SetOperation:
+		q.OrderBy = own.OrderBy
+		q.Limit = own.Limit
+		q.LimitAll = own.LimitAll
+		q.Offset = own.Offset
+		q.Fetch = own.Fetch
+	case *ast.ParenQuery:
+		q.OrderBy = own.OrderBy
+		q.Limit = own.Limit
+		q.LimitAll = own.LimitAll
+		q.Offset = own.Offset
+		q.Fetch = own.Fetch
+	}
+	return query
+}
+
+// queryResultClauses returns the result clauses already set on a query.
+func queryResultClauses(query ast.QueryExpr) *resultClauses {
+	switch q := query.(type) {
+	case *ast.SelectStmt:
+		return &resultClauses{OrderBy: q.OrderBy, Limit: q.Limit, LimitAll: q.LimitAll, Offset: q.Offset, Fetch: q.Fetch}
+	case *ast.SetOperation:
+		return &resultClauses{OrderBy: q.OrderBy, Limit: q.Limit, LimitAll: q.LimitAll, Offset: q.Offset, Fetch: q.Fetch}
+	case *ast.ParenQuery:
+		return &resultClauses{OrderBy: q.OrderBy, Limit: q.Limit, LimitAll: q.LimitAll, Offset: q.Offset, Fetch: q.Fetch}
+	default:
+		return &resultClauses{}
+	}
+}
+
+// stages returns the first and last step at which the clauses act on the
+// rows: sorting, then skipping with OFFSET, then limiting with LIMIT or
+// FETCH. Both are -1 when no clause is set.
+func (c *resultClauses) stages() (first, last int) {
+	first, last = -1, -1
+	for stage, set := range []bool{
+		c.OrderBy != nil,
+		c.Offset != nil,
+		c.Limit != nil || c.LimitAll || c.Fetch != nil,
+	} {
+		if !set {
+			continue
+		}
+		if first < 0 {
+			first = stage
+		}
+		last = stage
+	}
+	return first, last
+}
+
+// parseWithClause parses WITH [RECURSIVE] name [(columns)] AS (query), ...
+func parseWithClause(ts *TokenStream) (*ast.With, error) {
+	if err := ts.Consume(T_WITH); err != nil {
+		return nil, err
+	}
+
+	result := &ast.With{}
+	if _, val := ts.Current(); strings.ToUpper(val) == T_RECURSIVE {
+		result.Recursive = true
+		ts.Next()
+	}
+
+	for {
+		name, err := parseUnqualifiedName(ts, "common table expression name")
+		if err != nil {
+			return nil, err
+		}
+		cte := ast.CommonTableExpr{Name: name}
+
+		if _, val := ts.Current(); val == T_LPAREN {
+			columns, err := parseIdentifierList(ts)
+			if err != nil {
+				return nil, err
+			}
+			cte.Columns = columns
+		}
+
+		if err := ts.Consume(T_AS); err != nil {
+			return nil, err
+		}
+
+		query, err := parseSubquery(ts)
+		if err != nil {
+			return nil, err
+		}
+		cte.Query = query
+
+		result.CTEs = append(result.CTEs, cte)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	return result, nil
+}
+
+// isQueryStart reports whether the current token begins a query, as
+// opposed to a parenthesized expression or join. Besides SELECT and WITH,
+// a query may begin with a parenthesized query that is followed by a set
+// operator or a result clause: ((SELECT a FROM t) UNION SELECT b FROM s).
+func isQueryStart(ts *TokenStream) bool {
+	_, val := ts.Current()
+	switch strings.ToUpper(val) {
+	case T_SELECT, T_WITH:
+		return true
+	case T_LPAREN:
+	default:
+		return false
+	}
+
+	if !isParenthesizedQuery(ts) {
+		return false
+	}
+
+	ahead := ts.Lookahead()
+	for depth := 0; !ahead.IsEOF(); {
+		_, val := ahead.Current()
+		if val == T_LPAREN {
+			depth++
+		} else if val == T_RPAREN {
+			depth--
+		}
+		ahead.Next()
+		if depth == 0 {
+			break
+		}
+	}
+
+	_, val = ahead.Current()
+	upperVal := strings.ToUpper(val)
+	if _, ok := setOperationPrecedence[upperVal]; ok {
+		return true
+	}
+	switch upperVal {
+	case T_RPAREN, T_ORDER, T_LIMIT, T_OFFSET, T_FETCH:
+		return true
+	}
+	return false
+}
+
+// isParenthesizedQuery reports whether the current token opens parentheses
+// around a query, as in the source of INSERT INTO t (SELECT ...).
+func isParenthesizedQuery(ts *TokenStream) bool {
+	if _, val := ts.Current(); val != T_LPAREN {
+		return false
+	}
+
+	ahead := ts.Lookahead()
+	ahead.Next()
+	return isQueryStart(ahead)
+}
+
+// parseSetExpression combines operands with UNION, INTERSECT and EXCEPT by
+// precedence climbing. INTERSECT binds tighter than UNION and EXCEPT, which
+// associate to the left.
+func parseSetExpression(ts *TokenStream, minPrecedence int) (ast.QueryExpr, error) {
+	left, err := parseSetOperand(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		_, val := ts.Current()
+		operator := strings.ToUpper(val)
+
+		precedence, ok := setOperationPrecedence[operator]
+		if !ok || precedence <= minPrecedence {
+			return left, nil
+		}
+		ts.Next()
+
+		result := &ast.SetOperation{Operator: operator, Left: left}
+
+		_, val = ts.Current()
+		switch strings.ToUpper(val) {
+		case T_ALL:
+			result.All = true
+			ts.Next()
+		case T_DISTINCT:
+			ts.Next()
+		}
+
+		right, err := parseSetExpression(ts, precedence)
+		if err != nil {
+			return nil, err
+		}
+		result.Right = right
+
+		left = result
+	}
+}
+
+// parseSetOperand parses a bare SELECT or a parenthesized query, which may
+// carry its own ORDER BY and LIMIT.
+func parseSetOperand(ts *TokenStream) (ast.QueryExpr, error) {
+	if _, val := ts.Current(); val == T_LPAREN {
+		ts.Next()
+
+		query, err := parseQuery(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return nil, err
+		}
+		return query, nil
+	}
+
+	return parseSelectStatement(ts)
+}
+
+// parseResultClauses parses ORDER BY followed by LIMIT or FETCH and OFFSET,
+// which may come in either order.
+func parseResultClauses(ts *TokenStream) (*resultClauses, error) {
+	clauses := &resultClauses{}
+
+	lastClause := -1
+	for {
+		_, val := ts.Current()
+		upperVal := strings.ToUpper(val)
+
+		// OFFSET may also come before LIMIT or FETCH, as Postgres allows
+		offsetFirst := (upperVal == T_LIMIT || upperVal == T_FETCH) &&
+			lastClause == slices.Index(selectClauseOrder, T_OFFSET)
+		if offsetFirst {
+			if (upperVal == T_LIMIT && (clauses.Limit != nil || clauses.LimitAll)) || (upperVal == T_FETCH && clauses.Fetch != nil) {
+				return nil, fmt.Errorf("%w: duplicate %s clause", ErrSyntaxError, upperVal)
+			}
+		} else if err := checkClauseOrder(selectClauseOrder, upperVal, &lastClause); err != nil {
+			return nil, err
+		}
+
+		switch upperVal {
+		case T_ORDER:
+			ts.Next()
+			if err := ts.Consume(T_BY); err != nil {
+				return nil, err
+			}
+
+			orderBy, err := parseOrderByList(ts)
+			if err != nil {
+				return nil, err
+			}
+			clauses.OrderBy = orderBy
+		case T_LIMIT:
+			if clauses.Fetch != nil {
+				return nil, fmt.Errorf("%w: LIMIT and FETCH cannot be used together", ErrSyntaxError)
+			}
+			ts.Next()
+
+			if _, val := ts.Current(); strings.ToUpper(val) == T_ALL {
+				ts.Next()
+				clauses.LimitAll = true
+				continue
+			}
+
+			limit, err := parseRowCount(ts, T_LIMIT)
+			if err != nil {
+				return nil, err
+			}
+			clauses.Limit = limit
+		case T_OFFSET:
+			ts.Next()
+
+			offset, err := parseRowCount(ts, T_OFFSET)
+			if err != nil {
+				return nil, err
+			}
+			clauses.Offset = offset
+
+			if _, val := ts.Current(); strings.ToUpper(val) == T_ROW || strings.ToUpper(val) == T_ROWS {
+				ts.Next()
+			}
+		case T_FETCH:
+			if clauses.Limit != nil || clauses.LimitAll {
+				return nil, fmt.Errorf("%w: LIMIT and FETCH cannot be used together", ErrSyntaxError)
+			}
+			ts.Next()
+
+			fetch, err := parseFetchClause(ts)
+			if err != nil {
+				return nil, err
+			}
+			clauses.Fetch = fetch
+		default:
+			return clauses, nil
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"fmt"
+
+	"cockatoo/ast"
+)
+
+// ParseScript parses every statement of a script such as a migration file.
+// Statements are separated by semicolons outside of strings and comments;
+// empty statements, as in ";;" or a trailing ";", are skipped. Each
+// statement's SourceSpan locates its text in src.
+func ParseScript(src string) ([]ast.Statement, error) {
+	ts := NewTokenStream(src)
+	ts.Initialize()
+
+	var statements []ast.Statement
+	for !ts.IsEOF() {
+		if _, val := ts.Current(); val == T_SEMICOLON {
+			ts.Next()
+			continue
+		}
+
+		start := ts.currentPos
+		stmt, err := parseStatement(ts)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d at offset %d: %w", len(statements)+1, start, err)
+		}
+		statements = append(statements, stmt)
+	}
+
+	return statements, nil
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -10,6 +11,7 @@ import (
 	"cockatoo/ast"
 )
 
+// parseSelectStatement parses a single SELECT up to and including WINDOW.
 func parseSelectStatement(ts *TokenStream) (*ast.SelectStmt, error) {
 	result := &ast.SelectStmt{}
 
@@ -17,6 +19,10 @@ func parseSelectStatement(ts *TokenStream) (*ast.SelectStmt, error) {
 		return nil, err
 	}
 
+	if err := parseSetQuantifier(ts, result); err != nil {
+		return nil, err
+	}
+
 	projections, err := parseProjectionList(ts)
 	if err != nil {
 		return nil, err
@@ -27,20 +33,22 @@ func parseSelectStatement(ts *TokenStream) (*ast.SelectStmt, error) {
 		return nil, err
 	}
 
-	tableName, err := parseTableName(ts)
+	from, err := parseFromClause(ts)
 	if err != nil {
 		return nil, err
 	}
-	result.From = tableName
+	result.From = from
 
+	lastClause := -1
 	for {
 		_, val := ts.Current()
 		upperVal := strings.ToUpper(val)
+		if err := checkClauseOrder(selectClauseOrder, upperVal, &lastClause); err != nil {
+			return nil, err
+		}
+
 		switch upperVal {
 		case T_WHERE:
-			if result.Limit != nil {
-				return nil, fmt.Errorf("%w: WHERE clause must come before LIMIT", ErrSyntaxError)
-			}
 			ts.Next()
 
 			expr, err := parseExpression(ts)
@@ -48,147 +56,416 @@ func parseSelectStatement(ts *TokenStream) (*ast.SelectStmt, error) {
 				return nil, err
 			}
 			result.Selection = expr
-		case T_LIMIT:
+		case T_GROUP:
+			ts.Next()
+			if err := ts.Consume(T_BY); err != nil {
+				return nil, err
+			}
+
+			groupBy, err := parseGroupByList(ts)
+			if err != nil {
+				return nil, err
+			}
+			result.GroupBy = groupBy
+		case T_HAVING:
 			ts.Next()
 
-			limitStr, err := ts.ConsumeNumber()
+			expr, err := parseExpression(ts)
 			if err != nil {
-				return nil, fmt.Errorf("%w: expected number after LIMIT", ErrSyntaxError)
+				return nil, err
 			}
+			result.Having = expr
+		case T_WINDOW:
+			ts.Next()
 
-			limitVal, err := strconv.ParseUint(limitStr, 10, 64)
+			windows, err := parseWindowClause(ts)
 			if err != nil {
-				return nil, fmt.Errorf("%w: invalid LIMIT value", ErrSyntaxError)
+				return nil, err
 			}
-			result.Limit = &limitVal
-		case T_SEMICOLON:
-			return result, nil
+			result.Windows = windows
 		default:
-			if ts.IsEOF() {
-				return result, nil
-			}
-			return nil, fmt.Errorf("%w: unexpected token after SELECT statement", ErrSyntaxError)
+			// ORDER BY and the clauses after it belong to the enclosing
+			// query, which may be a set operation; see parseQuery
+			return result, nil
 		}
 	}
 }
 
-func parseProjectionList(ts *TokenStream) ([]ast.ProjectionItem, error) {
-	var projections []ast.ProjectionItem
+// parseSetQuantifier parses the optional ALL, DISTINCT or
+// DISTINCT ON (exprs) that follows SELECT. ALL is the default and leaves
+// the statement unchanged.
+func parseSetQuantifier(ts *TokenStream, stmt *ast.SelectStmt) error {
+	_, val := ts.Current()
 
-	for {
-		token, val := ts.Current()
+	switch strings.ToUpper(val) {
+	case T_ALL:
+		ts.Next()
+	case T_DISTINCT:
+		ts.Next()
+		stmt.Distinct = true
 
-		if val == T_STAR {
-			projections = append(projections, ast.ProjectionItem{IsWildcard: true})
-			ts.Next()
-			return projections, nil
+		if _, val := ts.Current(); strings.ToUpper(val) != T_ON {
+			return nil
 		}
+		ts.Next()
 
-		if token == sqllexer.IDENT {
-			columnName := val
-			projection := ast.ProjectionItem{
-				Expression: &ast.ColumnRef{Name: columnName},
-				IsWildcard: false,
-			}
-			ts.Next()
-
-			token, val = ts.Current()
+		if err := ts.Consume(T_LPAREN); err != nil {
+			return err
+		}
 
-			projections = append(projections, projection)
+		for {
+			expr, err := parseExpression(ts)
+			if err != nil {
+				return err
+			}
+			stmt.DistinctOn = append(stmt.DistinctOn, expr)
 
-			if _, val = ts.Current(); val == T_COMMA {
+			if _, val := ts.Current(); val == T_COMMA {
 				ts.Next()
 				continue
 			}
 			break
 		}
 
-		return nil, fmt.Errorf("%w: expected column name or *", ErrSyntaxError)
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return err
+		}
 	}
 
-	return projections, nil
+	return nil
 }
 
-func parseTableName(ts *TokenStream) (ast.TableRef, error) {
-	tableName, err := ts.ConsumeIdentifier()
-	if err != nil {
-		return ast.TableRef{}, fmt.Errorf("%w: expected table name", ErrSyntaxError)
+// checkClauseOrder rejects a clause keyword that appears after a clause
+// which must follow it, or that appears twice. last holds the position in
+// order of the previous clause and is advanced past keyword.
+func checkClauseOrder(order []string, keyword string, last *int) error {
+	position := slices.Index(order, keyword)
+	if position < 0 {
+		return nil
 	}
 
-	result := ast.TableRef{
-		Name: tableName,
+	if position == *last {
+		return fmt.Errorf("%w: duplicate %s clause", ErrSyntaxError, clauseName(keyword))
+	}
+	if position < *last {
+		return fmt.Errorf("%w: %s clause must come before %s", ErrSyntaxError,
+			clauseName(keyword), clauseName(order[*last]))
 	}
 
-	return result, nil
+	*last = position
+	return nil
 }
 
-func parseExpression(ts *TokenStream) (ast.Expr, error) {
-	left, err := parseSimpleExpression(ts)
-	if err != nil {
-		return nil, err
+func clauseName(keyword string) string {
+	switch keyword {
+	case T_GROUP:
+		return "GROUP BY"
+	case T_ORDER:
+		return "ORDER BY"
+	default:
+		return keyword
 	}
+}
 
+// parseGroupByList parses the items of a GROUP BY clause. Each item is an
+// expression or one of ROLLUP, CUBE and GROUPING SETS.
+func parseGroupByList(ts *TokenStream) ([]ast.Expr, error) {
+	var items []ast.Expr
+
+	for {
+		item, err := parseGroupingElement(ts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	return items, nil
+}
+
+func parseGroupingElement(ts *TokenStream) (ast.Expr, error) {
 	_, val := ts.Current()
-	upperVal := strings.ToUpper(val)
-	if upperVal == T_AND || upperVal == T_OR {
-		operator := upperVal
-		ts.Next() // consume and/or
 
-		right, err := parseExpression(ts)
+	switch strings.ToUpper(val) {
+	case T_ROLLUP, T_CUBE:
+		ts.Next()
+		return parseGroupingSet(ts, strings.ToUpper(val), parseGroupingColumns)
+	case T_GROUPING:
+		ts.Next()
+		if err := ts.Consume(T_SETS); err != nil {
+			return nil, err
+		}
+		return parseGroupingSet(ts, T_GROUPING+" "+T_SETS, func(ts *TokenStream) ([]ast.Expr, error) {
+			if _, val := ts.Current(); val == T_LPAREN {
+				return parseGroupingColumns(ts)
+			}
+
+			element, err := parseGroupingElement(ts)
+			if err != nil {
+				return nil, err
+			}
+			return []ast.Expr{element}, nil
+		})
+	default:
+		return parseExpression(ts)
+	}
+}
+
+// parseGroupingSet parses the parenthesized, comma separated sets of a
+// ROLLUP, CUBE or GROUPING SETS item, reading each one with parseSet.
+func parseGroupingSet(ts *TokenStream, kind string, parseSet func(*TokenStream) ([]ast.Expr, error)) (*ast.GroupingSet, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	result := &ast.GroupingSet{Type: kind}
+	for {
+		set, err := parseSet(ts)
 		if err != nil {
 			return nil, err
 		}
+		result.Sets = append(result.Sets, set)
 
-		return &ast.LogicalOp{
-			Left:     left,
-			Right:    right,
-			Operator: operator,
-		}, nil
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
 	}
 
-	return left, nil
+	return result, nil
 }
 
-func parseSimpleExpression(ts *TokenStream) (ast.Expr, error) {
-	columnName, err := ts.ConsumeIdentifier()
-	if err != nil {
-		return nil, fmt.Errorf("%w: expected column name", ErrSyntaxError)
+// parseGroupingColumns parses a single grouping column or a parenthesized
+// list of them, which may be empty.
+func parseGroupingColumns(ts *TokenStream) ([]ast.Expr, error) {
+	if _, val := ts.Current(); val != T_LPAREN {
+		expr, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		return []ast.Expr{expr}, nil
+	}
+	ts.Next()
+
+	columns := []ast.Expr{}
+	if _, val := ts.Current(); val == T_RPAREN {
+		ts.Next()
+		return columns, nil
+	}
+
+	for {
+		expr, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, expr)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// parseOrderByList parses the sort keys of an ORDER BY clause:
+// expr [ASC | DESC] [NULLS FIRST | NULLS LAST], separated by commas.
+func parseOrderByList(ts *TokenStream) ([]ast.OrderByItem, error) {
+	var items []ast.OrderByItem
+
+	for {
+		expr, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		item := ast.OrderByItem{Expression: expr}
+
+		_, val := ts.Current()
+		if upperVal := strings.ToUpper(val); upperVal == T_ASC || upperVal == T_DESC {
+			item.Direction = upperVal
+			ts.Next()
+		}
+
+		if _, val := ts.Current(); strings.ToUpper(val) == T_NULLS {
+			ts.Next()
+
+			_, val := ts.Current()
+			upperVal := strings.ToUpper(val)
+			if upperVal != T_FIRST && upperVal != T_LAST {
+				return nil, fmt.Errorf("%w: expected FIRST or LAST after NULLS, got %q", ErrSyntaxError, val)
+			}
+			item.Nulls = upperVal
+			ts.Next()
+		}
+
+		items = append(items, item)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
 	}
 
-	left := &ast.ColumnRef{Name: columnName}
+	return items, nil
+}
 
+// parseFetchClause parses the rest of FETCH {FIRST | NEXT} [n] {ROW | ROWS}
+// {ONLY | WITH TIES}. The count defaults to one row.
+func parseFetchClause(ts *TokenStream) (*ast.Fetch, error) {
 	_, val := ts.Current()
-	if _, ok := validOps[val]; !ok {
-		return nil, fmt.Errorf("%w: expected comparison operator (>, <, =, !=, >=, <=) in 'WHERE' clause, got %q", ErrSyntaxError, val)
+	if upperVal := strings.ToUpper(val); upperVal != T_FIRST && upperVal != T_NEXT {
+		return nil, fmt.Errorf("%w: expected FIRST or NEXT after FETCH, got %q", ErrSyntaxError, val)
+	}
+	ts.Next()
+
+	result := &ast.Fetch{Count: &ast.LiteralInt{Value: 1}}
+	if tokenType, _ := ts.Current(); tokenType == sqllexer.NUMBER || isParamStart(ts) {
+		count, err := parseRowCount(ts, T_FETCH)
+		if err != nil {
+			return nil, err
+		}
+		result.Count = count
 	}
 
-	operator := val
+	_, val = ts.Current()
+	if upperVal := strings.ToUpper(val); upperVal != T_ROW && upperVal != T_ROWS {
+		return nil, fmt.Errorf("%w: expected ROW or ROWS in FETCH clause, got %q", ErrSyntaxError, val)
+	}
 	ts.Next()
 
-	tokenType, val := ts.Current()
-	var right ast.Expr
+	_, val = ts.Current()
+	switch strings.ToUpper(val) {
+	case T_ONLY:
+		ts.Next()
+	case T_WITH:
+		ts.Next()
+		if err := ts.Consume(T_TIES); err != nil {
+			return nil, err
+		}
+		result.WithTies = true
+	default:
+		return nil, fmt.Errorf("%w: expected ONLY or WITH TIES in FETCH clause, got %q", ErrSyntaxError, val)
+	}
+
+	return result, nil
+}
 
-	switch tokenType {
-	case sqllexer.NUMBER:
-		intVal, err := strconv.ParseInt(val, 10, 64)
+// parseRowCount reads the row count of a LIMIT, OFFSET or FETCH clause: a
+// non-negative integer or a bind parameter.
+func parseRowCount(ts *TokenStream, clause string) (ast.Expr, error) {
+	if isParamStart(ts) {
+		return parseParam(ts)
+	}
+
+	countStr, err := ts.ConsumeNumber()
+	if err != nil {
+		return nil, fmt.Errorf("%w: expected number after %s", ErrSyntaxError, clause)
+	}
+
+	count, err := strconv.ParseInt(countStr, 10, 64)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("%w: invalid %s value", ErrSyntaxError, clause)
+	}
+
+	return &ast.LiteralInt{Value: count}, nil
+}
+
+func parseProjectionList(ts *TokenStream) ([]ast.ProjectionItem, error) {
+	var projections []ast.ProjectionItem
+
+	for {
+		projection, err := parseProjectionItem(ts)
 		if err != nil {
-			return nil, fmt.Errorf("%w: invalid integer", ErrSyntaxError)
+			return nil, err
 		}
-		right = &ast.LiteralInt{Value: intVal}
-		ts.Next()
-	case sqllexer.STRING:
-		right = &ast.LiteralString{Value: val}
+		projections = append(projections, projection)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	return projections, nil
+}
+
+func parseProjectionItem(ts *TokenStream) (ast.ProjectionItem, error) {
+	tokenType, val := ts.Current()
+
+	if val == T_STAR {
 		ts.Next()
-	case sqllexer.IDENT:
-		right = &ast.ColumnRef{Name: val}
+		return ast.ProjectionItem{IsWildcard: true}, nil
+	}
+
+	// the lexer reads "u.*" as the identifier "u." followed by "*"
+	if tokenType == sqllexer.IDENT && strings.HasSuffix(val, ".") {
 		ts.Next()
-	default:
-		return nil, fmt.Errorf("%w: expected value", ErrSyntaxError)
+		if err := ts.Consume(T_STAR); err != nil {
+			return ast.ProjectionItem{}, err
+		}
+		return ast.ProjectionItem{
+			IsWildcard: true,
+			Qualifier:  splitName(strings.TrimSuffix(val, ".")),
+		}, nil
+	}
+
+	expr, err := parseExpression(ts)
+	if err != nil {
+		return ast.ProjectionItem{}, err
+	}
+
+	alias, err := parseAlias(ts)
+	if err != nil {
+		return ast.ProjectionItem{}, err
 	}
 
-	return &ast.ComparisonOp{
-		Left:     left,
-		Right:    right,
-		Operator: operator,
+	return ast.ProjectionItem{
+		Expression: expr,
+		Alias:      alias,
+		IsWildcard: false,
 	}, nil
 }
+
+// parseAlias reads an optional "AS alias" or bare alias. It returns an empty
+// identifier when no alias follows.
+func parseAlias(ts *TokenStream) (ast.Identifier, error) {
+	tokenType, _ := ts.Current()
+
+	if tokenType == sqllexer.ALIAS_INDICATOR {
+		ts.Next()
+
+		// after AS even words the lexer knows as keywords, like TOP, are
+		// plain names unless they start a clause
+		if tokenType, val := ts.Current(); tokenType == sqllexer.KEYWORD && !isReservedWord(ts) {
+			ts.Next()
+			return ast.Identifier{Value: val}, nil
+		}
+
+		return parseUnqualifiedName(ts, "alias")
+	}
+
+	if tokenType == sqllexer.IDENT && !isReservedWord(ts) {
+		return parseUnqualifiedName(ts, "alias")
+	}
+
+	return ast.Identifier{}, nil
+}
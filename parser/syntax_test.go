@@ -46,6 +46,421 @@ func TestSyntaxErrors(t *testing.T) {
 			query:       "SELECT name FROM users WHERE (age > 18",
 			expectedErr: ErrSyntaxError,
 		},
+		{
+			name:        "unmatched closing parenthesis",
+			query:       "SELECT name FROM users WHERE age > 18)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "NOT without operand",
+			query:       "SELECT name FROM users WHERE NOT",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "malformed number",
+			query:       "INSERT INTO t VALUES (1.2.3)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unclosed function call",
+			query:       "SELECT count(id FROM users",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "AS without alias",
+			query:       "SELECT id AS FROM users",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "JOIN without condition",
+			query:       "SELECT * FROM a JOIN b",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "alias on a parenthesized table name",
+			query:       "SELECT * FROM (a) x",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "NATURAL without JOIN",
+			query:       "SELECT * FROM a NATURAL b",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "WHERE after LIMIT",
+			query:       "SELECT name FROM users LIMIT 1 WHERE age > 18",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "HAVING before GROUP BY",
+			query:       "SELECT city FROM users HAVING COUNT(*) > 1 GROUP BY city",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "duplicate WHERE clause",
+			query:       "SELECT name FROM users WHERE a = 1 WHERE b = 2",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "GROUP without BY",
+			query:       "SELECT city FROM users GROUP city",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "ORDER BY after LIMIT",
+			query:       "SELECT name FROM users LIMIT 5 ORDER BY name",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "LIMIT combined with FETCH",
+			query:       "SELECT name FROM users LIMIT 5 FETCH FIRST 1 ROW ONLY",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "LIMIT after OFFSET and FETCH",
+			query:       "SELECT name FROM users OFFSET 1 FETCH FIRST 1 ROW ONLY LIMIT 5",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "duplicate LIMIT around OFFSET",
+			query:       "SELECT name FROM users LIMIT 5 OFFSET 1 LIMIT 2",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "ORDER BY after OFFSET and LIMIT",
+			query:       "SELECT name FROM users OFFSET 1 LIMIT 5 ORDER BY name",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "NULLS without FIRST or LAST",
+			query:       "SELECT name FROM users ORDER BY name NULLS",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unclosed subquery",
+			query:       "SELECT a FROM t WHERE a IN (SELECT b FROM u",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "EXISTS without subquery",
+			query:       "SELECT a FROM t WHERE EXISTS a",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "NOT followed by comparison operator",
+			query:       "SELECT a FROM t WHERE a NOT = 1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "IS without NULL",
+			query:       "SELECT a FROM t WHERE a IS 1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "BETWEEN without AND",
+			query:       "SELECT a FROM t WHERE a BETWEEN 1 OR 2",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unterminated string",
+			query:       "SELECT a FROM t WHERE a = 'x",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "CASE without WHEN",
+			query:       "SELECT CASE status ELSE 1 END FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "CAST to unsupported type",
+			query:       "SELECT CAST(a AS blob) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "LIMIT before UNION",
+			query:       "SELECT a FROM t LIMIT 1 UNION SELECT b FROM u",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "UNION without right operand",
+			query:       "SELECT a FROM t UNION",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "WITH without parenthesized query",
+			query:       "WITH a AS SELECT 1 FROM x SELECT * FROM a",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "WITH without main query",
+			query:       "WITH a AS (SELECT 1 FROM x)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "OVER without window",
+			query:       "SELECT count(*) OVER FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "window frame bound without direction",
+			query:       "SELECT sum(a) OVER (ROWS BETWEEN UNBOUNDED AND CURRENT ROW) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "window frame starting with UNBOUNDED FOLLOWING",
+			query:       "SELECT sum(a) OVER (ROWS BETWEEN UNBOUNDED FOLLOWING AND UNBOUNDED FOLLOWING) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "window frame ending with UNBOUNDED PRECEDING",
+			query:       "SELECT sum(a) OVER (ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED PRECEDING) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "single window frame bound with an offset FOLLOWING",
+			query:       "SELECT sum(a) OVER (ROWS 5 FOLLOWING) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "single window frame bound UNBOUNDED FOLLOWING",
+			query:       "SELECT sum(a) OVER (ROWS UNBOUNDED FOLLOWING) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "window frame starting at the current row and ending before it",
+			query:       "SELECT sum(a) OVER (ROWS BETWEEN CURRENT ROW AND 1 PRECEDING) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "window frame starting after the current row and ending at it",
+			query:       "SELECT sum(a) OVER (RANGE BETWEEN 1 FOLLOWING AND CURRENT ROW) FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "WINDOW after ORDER BY",
+			query:       "SELECT a FROM t ORDER BY a WINDOW w AS (ORDER BY a)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DISTINCT ON without parentheses",
+			query:       "SELECT DISTINCT ON a, b FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DISTINCT ON with empty list",
+			query:       "SELECT DISTINCT ON () a FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unterminated quoted identifier",
+			query:       `SELECT "name FROM users`,
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unterminated bracket identifier",
+			query:       "SELECT [name FROM users",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "space in named parameter",
+			query:       "SELECT a FROM t WHERE a = : id",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "parameter number zero",
+			query:       "SELECT a FROM t WHERE a = $0",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "negative LIMIT",
+			query:       "SELECT a FROM t LIMIT -1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DEFAULT without VALUES",
+			query:       "INSERT INTO users DEFAULT",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DEFAULT VALUES after a column list",
+			query:       "INSERT INTO t (a) DEFAULT VALUES",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified name in column list",
+			query:       "INSERT INTO users (users.id) VALUES (1)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DEFAULT outside of VALUES",
+			query:       "SELECT DEFAULT FROM users",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "RETURNING without columns",
+			query:       "INSERT INTO users VALUES (1) RETURNING",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "INSERT SELECT without FROM",
+			query:       "INSERT INTO users SELECT 1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "ON CONFLICT without action",
+			query:       "INSERT INTO t VALUES (1) ON CONFLICT (id)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "ON CONFLICT DO with unknown action",
+			query:       "INSERT INTO t VALUES (1) ON CONFLICT DO DELETE",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "ON DUPLICATE KEY without UPDATE",
+			query:       "INSERT INTO t VALUES (1) ON DUPLICATE KEY SET a = 1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "SET assigns too few values",
+			query:       "INSERT INTO t VALUES (1) ON CONFLICT DO UPDATE SET (a, b) = (1)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "UPDATE without SET",
+			query:       "UPDATE users WHERE id = 1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "UPDATE assignment without value",
+			query:       "UPDATE users SET name =",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "UPDATE with WHERE before FROM",
+			query:       "UPDATE users SET a = 1 WHERE id = 1 FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DELETE without FROM",
+			query:       "DELETE users WHERE id = 1",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "DELETE with WHERE before USING",
+			query:       "DELETE FROM users WHERE id = 1 USING t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "TRUNCATE without table",
+			query:       "TRUNCATE TABLE",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "TRUNCATE RESTART without IDENTITY",
+			query:       "TRUNCATE logs RESTART",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "TRUNCATE with alias",
+			query:       "TRUNCATE logs l",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "MERGE without WHEN clause",
+			query:       "MERGE INTO t USING s ON t.id = s.id",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "MERGE INSERT for matched rows",
+			query:       "MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN INSERT VALUES (1)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "MERGE INSERT DEFAULT VALUES after a column list",
+			query:       "MERGE INTO t USING s ON t.id = s.id WHEN NOT MATCHED THEN INSERT (a) DEFAULT VALUES",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "MERGE DELETE for rows that did not match",
+			query:       "MERGE INTO t USING s ON t.id = s.id WHEN NOT MATCHED THEN DELETE",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "MERGE INSERT with several rows",
+			query:       "MERGE INTO t USING s ON t.id = s.id WHEN NOT MATCHED THEN INSERT VALUES (1), (2)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "NOT without NULL in column definition",
+			query:       "CREATE TABLE t (id INT NOT)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "PRIMARY without KEY",
+			query:       "CREATE TABLE t (id INT PRIMARY)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "CHECK without parentheses",
+			query:       "CREATE TABLE t (id INT CHECK id > 0)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unknown referential action",
+			query:       "CREATE TABLE t (id INT REFERENCES u (id) ON DELETE DROP)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "unknown column constraint",
+			query:       "CREATE TABLE t (id INT SPARKLY)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified column name in CREATE TABLE",
+			query:       "CREATE TABLE t (t.id INT)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified constraint name",
+			query:       "CREATE TABLE t (id INT CONSTRAINT s.pk PRIMARY KEY)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified ON CONFLICT constraint name",
+			query:       "INSERT INTO t VALUES (1) ON CONFLICT ON CONSTRAINT s.pk DO NOTHING",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified column alias",
+			query:       "SELECT a AS x.y FROM t",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified table alias",
+			query:       "SELECT a FROM t s.u",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified derived table alias",
+			query:       "SELECT a FROM (SELECT a FROM t) AS s.u",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified common table expression name",
+			query:       "WITH s.x AS (SELECT a FROM t) SELECT a FROM x",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified window name after OVER",
+			query:       "SELECT sum(a) OVER s.w FROM t WINDOW w AS (ORDER BY a)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "qualified window name in WINDOW clause",
+			query:       "SELECT sum(a) OVER w FROM t WINDOW s.w AS (ORDER BY a)",
+			expectedErr: ErrSyntaxError,
+		},
 		{
 			name:        "invalid comparison operator",
 			query:       "SELECT name FROM users WHERE age <=> 18",
@@ -95,6 +510,16 @@ func TestStructuralValidation(t *testing.T) {
 			query:       "SELECT * FROM users WHERE",
 			expectedErr: ErrSyntaxError,
 		},
+		{
+			name:        "INSERT row with fewer values than columns",
+			query:       "INSERT INTO users (id, name) VALUES (1, 'x'), (2)",
+			expectedErr: ErrSyntaxError,
+		},
+		{
+			name:        "INSERT rows of different lengths",
+			query:       "INSERT INTO users VALUES (1, 'x'), (2)",
+			expectedErr: ErrSyntaxError,
+		},
 	}
 
 	for _, tt := range tests {
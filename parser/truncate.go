@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"strings"
+
+	"cockatoo/ast"
+)
+
+// parseTruncateStatement parses TRUNCATE [TABLE] name, ... followed by
+// optional RESTART IDENTITY or CONTINUE IDENTITY and CASCADE or RESTRICT.
+// CONTINUE IDENTITY and RESTRICT are the defaults.
+func parseTruncateStatement(ts *TokenStream) (*ast.TruncateStmt, error) {
+	if err := ts.Consume(T_TRUNCATE); err != nil {
+		return nil, err
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_TABLE {
+		ts.Next()
+	}
+
+	result := &ast.TruncateStmt{}
+	for {
+		table, err := parseQualifiedTableName(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Tables = append(result.Tables, table)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	_, val := ts.Current()
+	switch strings.ToUpper(val) {
+	case T_RESTART:
+		ts.Next()
+		if err := ts.Consume(T_IDENTITY); err != nil {
+			return nil, err
+		}
+		result.RestartIdentity = true
+	case T_CONTINUE:
+		ts.Next()
+		if err := ts.Consume(T_IDENTITY); err != nil {
+			return nil, err
+		}
+	}
+
+	_, val = ts.Current()
+	switch strings.ToUpper(val) {
+	case T_CASCADE:
+		ts.Next()
+		result.Cascade = true
+	case T_RESTRICT:
+		ts.Next()
+	}
+
+	if err := expectStatementEnd(ts, T_TRUNCATE); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
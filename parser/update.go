@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"cockatoo/ast"
+)
+
+// parseUpdateStatement parses UPDATE table [[AS] alias] SET ... followed by
+// optional FROM, WHERE and RETURNING clauses.
+func parseUpdateStatement(ts *TokenStream) (*ast.UpdateStmt, error) {
+	if err := ts.Consume(T_UPDATE); err != nil {
+		return nil, err
+	}
+
+	table, err := parseTableName(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ast.UpdateStmt{
+		Table: table,
+	}
+
+	if err := ts.Consume(T_SET); err != nil {
+		return nil, err
+	}
+
+	set, err := parseAssignmentList(ts)
+	if err != nil {
+		return nil, err
+	}
+	result.Set = set
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_FROM {
+		ts.Next()
+
+		from, err := parseFromClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.From = from
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_WHERE {
+		ts.Next()
+
+		expr, err := parseExpression(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Selection = expr
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_RETURNING {
+		returning, err := parseReturningClause(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Returning = returning
+	}
+
+	if err := expectStatementEnd(ts, T_UPDATE); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// parseAssignmentList parses the items of a SET list: col = value or
+// (a, b) = (x, y), separated by commas.
+func parseAssignmentList(ts *TokenStream) ([]ast.Assignment, error) {
+	var assignments []ast.Assignment
+
+	for {
+		assignment, err := parseAssignment(ts)
+		if err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, assignment)
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	return assignments, nil
+}
+
+func parseAssignment(ts *TokenStream) (ast.Assignment, error) {
+	if _, val := ts.Current(); val != T_LPAREN {
+		column, err := parseUnqualifiedName(ts, "column name")
+		if err != nil {
+			return ast.Assignment{}, fmt.Errorf("%w: expected column name in SET", ErrSyntaxError)
+		}
+		if err := ts.Consume(T_EQ); err != nil {
+			return ast.Assignment{}, err
+		}
+
+		value, err := parseValueOrDefault(ts)
+		if err != nil {
+			return ast.Assignment{}, err
+		}
+		return ast.Assignment{Columns: []ast.Identifier{column}, Values: []ast.Expr{value}}, nil
+	}
+
+	columns, err := parseIdentifierList(ts)
+	if err != nil {
+		return ast.Assignment{}, err
+	}
+	if err := ts.Consume(T_EQ); err != nil {
+		return ast.Assignment{}, err
+	}
+
+	// a parenthesized query supplies all of the values as one row
+	if _, val := ts.Current(); val == T_LPAREN {
+		ts.Next()
+		if isQueryStart(ts) {
+			query, err := parseQuery(ts)
+			if err != nil {
+				return ast.Assignment{}, err
+			}
+			if err := ts.Consume(T_RPAREN); err != nil {
+				return ast.Assignment{}, err
+			}
+			return ast.Assignment{Columns: columns, Values: []ast.Expr{&ast.Subquery{Query: query}}}, nil
+		}
+
+		var values []ast.Expr
+		for {
+			value, err := parseValueOrDefault(ts)
+			if err != nil {
+				return ast.Assignment{}, err
+			}
+			values = append(values, value)
+
+			if _, val := ts.Current(); val == T_COMMA {
+				ts.Next()
+				continue
+			}
+			break
+		}
+
+		if err := ts.Consume(T_RPAREN); err != nil {
+			return ast.Assignment{}, err
+		}
+
+		if len(values) != len(columns) {
+			return ast.Assignment{}, fmt.Errorf("%w: SET assigns %d values to %d columns",
+				ErrSyntaxError, len(values), len(columns))
+		}
+		return ast.Assignment{Columns: columns, Values: values}, nil
+	}
+
+	return ast.Assignment{}, fmt.Errorf("%w: expected ( after = in multiple column SET", ErrSyntaxError)
+}
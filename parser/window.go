@@ -0,0 +1,243 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"cockatoo/ast"
+)
+
+// parseOverClause parses what follows OVER: a window name or a
+// parenthesized window specification.
+func parseOverClause(ts *TokenStream) (*ast.WindowSpec, error) {
+	if err := ts.Consume(T_OVER); err != nil {
+		return nil, err
+	}
+
+	if _, val := ts.Current(); val != T_LPAREN {
+		name, err := parseUnqualifiedName(ts, "window name")
+		if err != nil {
+			return nil, err
+		}
+		return &ast.WindowSpec{Name: name}, nil
+	}
+
+	return parseWindowSpec(ts)
+}
+
+// parseWindowSpec parses ([name] [PARTITION BY ...] [ORDER BY ...] [frame]).
+// The leading name refers to a window from the WINDOW clause that this
+// specification builds on.
+func parseWindowSpec(ts *TokenStream) (*ast.WindowSpec, error) {
+	if err := ts.Consume(T_LPAREN); err != nil {
+		return nil, err
+	}
+
+	result := &ast.WindowSpec{}
+
+	if !isWindowSpecKeyword(ts) {
+		if _, val := ts.Current(); val != T_RPAREN {
+			name, err := parseUnqualifiedName(ts, "window name")
+			if err != nil {
+				return nil, err
+			}
+			result.Name = name
+		}
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_PARTITION {
+		ts.Next()
+		if err := ts.Consume(T_BY); err != nil {
+			return nil, err
+		}
+
+		for {
+			expr, err := parseExpression(ts)
+			if err != nil {
+				return nil, err
+			}
+			result.PartitionBy = append(result.PartitionBy, expr)
+
+			if _, val := ts.Current(); val == T_COMMA {
+				ts.Next()
+				continue
+			}
+			break
+		}
+	}
+
+	if _, val := ts.Current(); strings.ToUpper(val) == T_ORDER {
+		ts.Next()
+		if err := ts.Consume(T_BY); err != nil {
+			return nil, err
+		}
+
+		orderBy, err := parseOrderByList(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.OrderBy = orderBy
+	}
+
+	if _, val := ts.Current(); isFrameUnits(val) {
+		frame, err := parseWindowFrame(ts)
+		if err != nil {
+			return nil, err
+		}
+		result.Frame = frame
+	}
+
+	if err := ts.Consume(T_RPAREN); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func isWindowSpecKeyword(ts *TokenStream) bool {
+	_, val := ts.Current()
+	upperVal := strings.ToUpper(val)
+	return upperVal == T_PARTITION || upperVal == T_ORDER || isFrameUnits(val)
+}
+
+func isFrameUnits(val string) bool {
+	switch strings.ToUpper(val) {
+	case T_ROWS, T_RANGE, T_GROUPS:
+		return true
+	default:
+		return false
+	}
+}
+
+// frameBoundOrder ranks the frame bound types from the start of a
+// partition to its end. A frame may not start after it ends.
+var frameBoundOrder = map[string]int{
+	T_UNBOUNDED + " " + T_PRECEDING: 0,
+	T_PRECEDING:                     1,
+	T_CURRENT + " " + T_ROW:         2,
+	T_FOLLOWING:                     3,
+	T_UNBOUNDED + " " + T_FOLLOWING: 4,
+}
+
+// parseWindowFrame parses {ROWS | RANGE | GROUPS} followed by a single start
+// bound or BETWEEN start AND end.
+func parseWindowFrame(ts *TokenStream) (*ast.WindowFrame, error) {
+	_, val := ts.Current()
+	result := &ast.WindowFrame{Units: strings.ToUpper(val)}
+	ts.Next()
+
+	if _, val := ts.Current(); strings.ToUpper(val) != T_BETWEEN {
+		start, err := parseFrameBound(ts)
+		if err != nil {
+			return nil, err
+		}
+		// a single bound is the start of a frame that ends at the current
+		// row, so it cannot lie after it
+		if frameBoundOrder[start.Type] > frameBoundOrder[T_CURRENT+" "+T_ROW] {
+			return nil, fmt.Errorf("%w: frame starting with %s must use BETWEEN", ErrSyntaxError, start.Type)
+		}
+		result.Start = start
+		return result, nil
+	}
+	ts.Next()
+
+	start, err := parseFrameBound(ts)
+	if err != nil {
+		return nil, err
+	}
+	if start.Type == T_UNBOUNDED+" "+T_FOLLOWING {
+		return nil, fmt.Errorf("%w: frame cannot start with %s", ErrSyntaxError, start.Type)
+	}
+	result.Start = start
+
+	if err := ts.Consume(T_AND); err != nil {
+		return nil, err
+	}
+
+	end, err := parseFrameBound(ts)
+	if err != nil {
+		return nil, err
+	}
+	if end.Type == T_UNBOUNDED+" "+T_PRECEDING {
+		return nil, fmt.Errorf("%w: frame cannot end with %s", ErrSyntaxError, end.Type)
+	}
+	if frameBoundOrder[start.Type] > frameBoundOrder[end.Type] {
+		return nil, fmt.Errorf("%w: frame cannot start with %s and end with %s", ErrSyntaxError, start.Type, end.Type)
+	}
+	result.End = &end
+
+	return result, nil
+}
+
+// parseFrameBound parses UNBOUNDED PRECEDING, UNBOUNDED FOLLOWING,
+// CURRENT ROW, or an offset followed by PRECEDING or FOLLOWING.
+func parseFrameBound(ts *TokenStream) (ast.FrameBound, error) {
+	_, val := ts.Current()
+
+	switch strings.ToUpper(val) {
+	case T_UNBOUNDED:
+		ts.Next()
+
+		_, val := ts.Current()
+		direction := strings.ToUpper(val)
+		if direction != T_PRECEDING && direction != T_FOLLOWING {
+			return ast.FrameBound{}, fmt.Errorf("%w: expected PRECEDING or FOLLOWING after UNBOUNDED, got %q", ErrSyntaxError, val)
+		}
+		ts.Next()
+
+		return ast.FrameBound{Type: T_UNBOUNDED + " " + direction}, nil
+	case T_CURRENT:
+		ts.Next()
+		if err := ts.Consume(T_ROW); err != nil {
+			return ast.FrameBound{}, err
+		}
+
+		return ast.FrameBound{Type: T_CURRENT + " " + T_ROW}, nil
+	}
+
+	offset, err := parseExpression(ts)
+	if err != nil {
+		return ast.FrameBound{}, err
+	}
+
+	_, val = ts.Current()
+	direction := strings.ToUpper(val)
+	if direction != T_PRECEDING && direction != T_FOLLOWING {
+		return ast.FrameBound{}, fmt.Errorf("%w: expected PRECEDING or FOLLOWING after frame offset, got %q", ErrSyntaxError, val)
+	}
+	ts.Next()
+
+	return ast.FrameBound{Type: direction, Offset: offset}, nil
+}
+
+// parseWindowClause parses the definitions of a WINDOW clause:
+// name AS (spec), ...
+func parseWindowClause(ts *TokenStream) ([]ast.NamedWindow, error) {
+	var windows []ast.NamedWindow
+
+	for {
+		name, err := parseUnqualifiedName(ts, "window name")
+		if err != nil {
+			return nil, err
+		}
+
+		if err := ts.Consume(T_AS); err != nil {
+			return nil, err
+		}
+
+		spec, err := parseWindowSpec(ts)
+		if err != nil {
+			return nil, err
+		}
+
+		windows = append(windows, ast.NamedWindow{Name: name, Spec: spec})
+
+		if _, val := ts.Current(); val == T_COMMA {
+			ts.Next()
+			continue
+		}
+		break
+	}
+
+	return windows, nil
+}